@@ -11,7 +11,7 @@ const (
 	basepath   = "/v1/"
 	host       = "api.chargehound.com"
 	protocol   = "https://"
-	version    = "8.6.2"
+	version    = "9.0.0"
 
 	defaultHTTPTimeout = 60 * time.Second
 )
@@ -31,37 +31,71 @@ type Client struct {
 	APIVersion string
 	// The client http timeout.
 	HTTPClient *http.Client
+	// The policy for retrying failed requests. Requests are not retried if nil.
+	RetryPolicy *RetryPolicy
+	// Generate an idempotency key for POST requests that don't set one.
+	AutoIdempotencyKeys bool
+	// The maximum size of a response body in bytes. Zero means no limit.
+	MaxResponseBytes int64
+	// Validate params before sending create, update and submit requests.
+	ValidateParams bool
 	// The disputes resource.
 	Disputes *Disputes
+	// The files resource.
+	Files *Files
+	// The templates resource.
+	Templates *Templates
 }
 
 // Chargehound client optional params.
 type ClientParams struct {
 	// The API version
 	APIVersion string
+	// The policy for retrying failed requests. See DefaultRetryPolicy.
+	RetryPolicy *RetryPolicy
+	// Generate an idempotency key for POST requests that don't set one.
+	AutoIdempotencyKeys bool
+	// The maximum size of a response body in bytes. Zero means no limit.
+	MaxResponseBytes int64
+	// Validate params before sending create, update and submit requests.
+	ValidateParams bool
 }
 
 // Creates a new chargehound client with the specified api key and the default configuration.
 func New(key string, params *ClientParams) *Client {
 
 	var apiVersion string
+	var retryPolicy *RetryPolicy
+	var autoIdempotencyKeys bool
+	var maxResponseBytes int64
+	var validateParams bool
 	if params != nil {
 		apiVersion = params.APIVersion
+		retryPolicy = params.RetryPolicy
+		autoIdempotencyKeys = params.AutoIdempotencyKeys
+		maxResponseBytes = params.MaxResponseBytes
+		validateParams = params.ValidateParams
 	} else {
 		apiVersion = APIVersion
 	}
 
 	ch := Client{
-		APIKey:     key,
-		Basepath:   basepath,
-		Host:       host,
-		HTTPClient: &http.Client{Timeout: defaultHTTPTimeout},
-		Protocol:   protocol,
-		Version:    version,
-		APIVersion: apiVersion,
+		APIKey:              key,
+		Basepath:            basepath,
+		Host:                host,
+		HTTPClient:          &http.Client{Timeout: defaultHTTPTimeout},
+		Protocol:            protocol,
+		Version:             version,
+		APIVersion:          apiVersion,
+		RetryPolicy:         retryPolicy,
+		AutoIdempotencyKeys: autoIdempotencyKeys,
+		MaxResponseBytes:    maxResponseBytes,
+		ValidateParams:      validateParams,
 	}
 
 	ch.Disputes = &Disputes{client: &ch}
+	ch.Files = &Files{client: &ch}
+	ch.Templates = &Templates{client: &ch}
 
 	return &ch
 }
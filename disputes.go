@@ -1,7 +1,7 @@
 package chargehound
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -20,19 +20,19 @@ type Dispute struct {
 	// A unique identifier for the dispute. This id is set by the payment processor of the dispute.
 	ID string `json:"id"`
 	// State of the dispute. One of `needs_response`,`submitted`, `under_review`, `won`, `lost`, `warning_needs_response`, `warning_under_review`, `warning_closed` , `response_disabled`, `charge_refunded`, `accepted`, `queued`.
-	State string `json:"state"`
+	State DisputeState `json:"state"`
 	// Reason for the dispute. One of `fraudulent`, `unrecognized`, `general`, `duplicate`, `subscription_canceled`, `product_unacceptable`, `product_not_received`, `credit_not_processed`, `incorrect_account_details`, `insufficient_funds`, `bank_cannot_process`, `debit_not_authorized`, `goods_services_returned_or_refused`, `goods_services_cancelled` |
-	Reason string `json:"reason"`
+	Reason DisputeReason `json:"reason"`
 	// ISO 8601 timestamp - when the charge was made.
-	ChargedAt string `json:"charged_at"`
+	ChargedAt Timestamp `json:"charged_at"`
 	// ISO 8601 timestamp - when the charge was disputed.
-	DisputedAt string `json:"disputed_at"`
+	DisputedAt Timestamp `json:"disputed_at"`
 	// ISO 8601 timestamp - when dispute evidence needs to be disputed by.
-	DueBy string `json:"due_by"`
+	DueBy Timestamp `json:"due_by"`
 	// ISO 8601 timestamp - when dispute evidence was submitted.
-	SubmittedAt string `json:"submitted_at"`
+	SubmittedAt Timestamp `json:"submitted_at"`
 	// ISO 8601 timestamp - when the dispute was resolved.
-	ClosedAt string `json:"closed_at"`
+	ClosedAt Timestamp `json:"closed_at"`
 	// Number of times the dispute evidence has been submitted.
 	SubmittedCount int `json:"submitted_count"`
 	// Id of the template attached to the dispute.
@@ -73,11 +73,11 @@ type Dispute struct {
 	// Billing address zip of the charge.
 	AddressZip string `json:"address_zip"`
 	// State of address check (if available). One of `pass`, `fail`, `unavailable`, `checked`.
-	AddressLine1Check string `json:"address_line1_check"`
+	AddressLine1Check CheckResult `json:"address_line1_check"`
 	// State of address zip check (if available). One of `pass`, `fail`, `unavailable`, `checked`.
-	AddressZipCheck string `json:"address_zip_check"`
+	AddressZipCheck CheckResult `json:"address_zip_check"`
 	// State of cvc check (if available). One of `pass`, `fail`, `unavailable`, `checked`.
-	CVCCheck string `json:"cvc_check"`
+	CVCCheck CheckResult `json:"cvc_check"`
 	// The descriptor that appears on the customer's credit card statement for this change.
 	StatementDescriptor string `json:"statement_descriptor"`
 	// The account id for Connected accounts that are charged directly through Stripe (if any)
@@ -85,19 +85,23 @@ type Dispute struct {
 	// Id of the connected account for this dispute (if multiple accounts are connected)
 	Account string `json:"account"`
 	// The kind for the dispute, 'chargeback', 'retrieval' or 'pre_arbitration'.
-	Kind string `json:"kind"`
+	Kind DisputeKind `json:"kind"`
 	// ISO 8601 timestamp.
-	Created string `json:"created"`
+	Created Timestamp `json:"created"`
 	// ISO 8601 timestamp.
-	Updated string `json:"updated"`
+	Updated Timestamp `json:"updated"`
 	// The source of the dispute. One of `mock`, `braintree`, `api` or `stripe`
-	Source string `json:"source"`
+	Source DisputeSource `json:"source"`
 	// The payment processor of the dispute. One of `braintree` or `stripe`
-	Processor string `json:"processor"`
+	Processor Processor `json:"processor"`
 	// Custom URL with dispute information.
 	ReferenceURL string `json:"reference_url"`
 	// Data about the API response that created dispute.
 	Response HTTPResponse `json:"-"`
+	// The raw JSON of the dispute. See Get.
+	Raw json.RawMessage `json:"-"`
+	// Attributes of the dispute that have no field yet, by name.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Dispute product data. See https://www.chargehound.com/docs/api/2021-09-15/#product-data.
@@ -115,25 +119,25 @@ type Product struct {
 
 // CorrespondenceItem for dispute correspondence data. See https://www.chargehound.com/docs/api/2021-09-15/#customer-correspondence.
 type CorrespondenceItem struct {
-	To      string `json:"to,omitempty"`
-	From    string `json:"from,omitempty"`
-	Sent    string `json:"sent,omitempty"`
-	Subject string `json:"subject,omitempty"`
-	Body    string `json:"body,omitempty"`
-	Caption string `json:"caption,omitempty"`
+	To      string     `json:"to,omitempty"`
+	From    string     `json:"from,omitempty"`
+	Sent    *Timestamp `json:"sent,omitempty"`
+	Subject string     `json:"subject,omitempty"`
+	Body    string     `json:"body,omitempty"`
+	Caption string     `json:"caption,omitempty"`
 }
 
 // PastPayment for customer past payments. See https://www.chargehound.com/docs/api/2021-09-15/#past-payments.
 type PastPayment struct {
-	ID              string      `json:"id,omitempty"`
-	Amount          int         `json:"amount,omitempty"`
-	Currency        string      `json:"currency,omitempty"`
-	ChargedAt       interface{} `json:"charged_at,omitempty"`
-	UserId          string      `json:"user_id,omitempty"`
-	ItemDescription string      `json:"item_description,omitempty"`
-	IPAddress       string      `json:"ip_address,omitempty"`
-	ShippingAddress string      `json:"shipping_address,omitempty"`
-	DeviceId        string      `json:"device_id,omitempty"`
+	ID              string     `json:"id,omitempty"`
+	Amount          int        `json:"amount,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	ChargedAt       *Timestamp `json:"charged_at,omitempty"`
+	UserId          string     `json:"user_id,omitempty"`
+	ItemDescription string     `json:"item_description,omitempty"`
+	IPAddress       string     `json:"ip_address,omitempty"`
+	ShippingAddress string     `json:"shipping_address,omitempty"`
+	DeviceId        string     `json:"device_id,omitempty"`
 }
 
 // The type returned by a list disputes request. See https://www.chargehound.com/docs/api/2021-09-15/#retrieving-a-list-of-disputes.
@@ -144,6 +148,8 @@ type DisputeList struct {
 	Object   string       `json:"object"`
 	URL      string       `json:"url"`
 	Response HTTPResponse `json:"-"`
+	// Attributes of the list that have no field yet, by name.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // The type returned by a dispute response request.
@@ -155,6 +161,10 @@ type Response struct {
 	Evidence       map[string]interface{} `json:"evidence"`
 	ResponseURL    string                 `json:"response_url"`
 	Response       HTTPResponse           `json:"-"`
+	// The raw JSON of the response. See Get.
+	Raw json.RawMessage `json:"-"`
+	// Attributes of the response that have no field yet, by name.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Params for a retrieve dispute request. See https://www.chargehound.com/docs/api/2021-09-15/#retrieving-a-dispute.
@@ -169,6 +179,8 @@ type RetrieveDisputeParams struct {
 type AcceptDisputeParams struct {
 	// The dispute id.
 	ID string
+	// A unique key to safely retry the request without accepting twice. (optional)
+	IdempotencyKey string
 	// Optional http client for the request. Typically needed when using App Engine.
 	OptHTTPClient *http.Client
 }
@@ -178,7 +190,27 @@ type ListDisputesParams struct {
 	Limit         int
 	StartingAfter string
 	EndingBefore  string
-	State         []string
+	State         []DisputeState
+	// Only disputes created in the range. (optional)
+	Created *DateRange
+	// Only disputes disputed in the range. (optional)
+	DisputedAt *DateRange
+	// Only disputes with evidence due in the range. (optional)
+	DueBy *DateRange
+	// Only disputes with one of the reasons. (optional)
+	Reason []DisputeReason
+	// Only disputes of one of the kinds. (optional)
+	Kind []DisputeKind
+	// Only disputes from one of the payment processors. (optional)
+	Processor []Processor
+	// Only disputes for one of the connected account ids. (optional)
+	Account []string
+	// Only disputes in one of the currencies. (optional)
+	Currency []string
+	// The maximum number of disputes returned by ListAll, across all pages. Zero means no limit.
+	MaxResults int
+	// The number of pages ListAll fetches ahead of the caller in the background. Zero fetches each page when it is needed.
+	Prefetch int
 	// Optional http client for the request. Typically needed when using App Engine.
 	OptHTTPClient *http.Client
 }
@@ -206,6 +238,14 @@ type UpdateDisputeParams struct {
 	Correspondence []CorrespondenceItem
 	PastPayments   []PastPayment
 	ReferenceURL   string
+	// Fields to send even when they hold their zero value, so they can be set
+	// to false, "" or an empty list, e.g. UpdateFieldForce. (optional)
+	ForceSendFields []UpdateField
+	// Fields to send as null, clearing them on the dispute, e.g.
+	// UpdateFieldReferenceURL. (optional)
+	NullFields []UpdateField
+	// A unique key to safely retry a submit request without submitting twice. (optional)
+	IdempotencyKey string
 	// Optional http client for the request. Typically needed when using App Engine.
 	OptHTTPClient *http.Client
 }
@@ -218,21 +258,21 @@ type CreateDisputeParams struct {
 	// The id of the charged customer in your payment processor. For Stripe looks like `cus_XXX`. (optional)
 	Customer string `json:"customer,omitempty"`
 	// The bank provided reason for the dispute. One of `general`, `fraudulent`, `duplicate`, `subscription_canceled`, `product_unacceptable`, `product_not_received`, `unrecognized`, `credit_not_processed`, `incorrect_account_details`, `insufficient_funds`, `bank_cannot_process`, `debit_not_authorized`.
-	Reason string `json:"reason"`
+	Reason DisputeReason `json:"reason"`
 	// ISO 8601 timestamp - when the charge was made.
-	ChargedAt string `json:"charged_at"`
+	ChargedAt Timestamp `json:"charged_at"`
 	// ISO 8601 timestamp - when the charge was disputed.
-	DisputedAt string `json:"disputed_at"`
+	DisputedAt Timestamp `json:"disputed_at"`
 	// ISO 8601 timestamp - when dispute evidence needs to be disputed by.
-	DueBy string `json:"due_by"`
+	DueBy Timestamp `json:"due_by"`
 	// The currency code of the disputed charge. e.g. 'USD'.
 	Currency string `json:"currency"`
 	// The amount of the disputed charge. Amounts are in cents (or other minor currency unit.)
 	Amount int `json:"amount"`
 	// The payment processor for the charge. One of `braintree` or `stripe`. (optional)
-	Processor string `json:"processor,omitempty"`
+	Processor Processor `json:"processor,omitempty"`
 	// The state of the dispute. One of `needs_response`, `warning_needs_response`. (optional)
-	State string `json:"state,omitempty"`
+	State DisputeState `json:"state,omitempty"`
 	// The currency code of the dispute balance withdrawal. e.g. 'USD'. (optional)
 	ReversalCurrency string `json:"reversal_currency,omitempty"`
 	// The amount of the dispute fee. Amounts are in cents (or other minor currency unit.) (optional)
@@ -246,11 +286,11 @@ type CreateDisputeParams struct {
 	// How many times has dispute evidence been submitted. (optional)
 	SubmittedCount int `json:"submitted_count,omitempty"`
 	// State of address check (if available). One of `pass`, `fail`, `unavailable`, `checked`. (optional)
-	AddressLine1Check string `json:"address_line1_check,omitempty"`
+	AddressLine1Check CheckResult `json:"address_line1_check,omitempty"`
 	// State of address zip check (if available). One of `pass`, `fail`, `unavailable`, `checked`. (optional)
-	AddressZipCheck string `json:"address_zip_check,omitempty"`
+	AddressZipCheck CheckResult `json:"address_zip_check,omitempty"`
 	// State of cvc check (if available). One of `pass`, `fail`, `unavailable`, `checked`. (optional)
-	CVCCheck string `json:"cvc_check,omitempty"`
+	CVCCheck CheckResult `json:"cvc_check,omitempty"`
 	// The id of the template to use. (optional)
 	Template string `json:"template,omitempty"`
 	// Key value pairs to hydrate the template's evidence fields. (optional)
@@ -265,17 +305,44 @@ type CreateDisputeParams struct {
 	// Set the account id for Connected accounts that are charged directly through Stripe. (optional)
 	AccountID string `json:"account_id,omitempty"`
 	// Set the kind for the dispute, 'chargeback', 'retrieval' or 'pre_arbitration'. (optional)
-	Kind string `json:"kind,omitempty"`
+	Kind DisputeKind `json:"kind,omitempty"`
 	// Submit dispute evidence immediately after creation. (optional)
 	Submit bool `json:"submit,omitempty"`
 	// Queue dispute for submission immediately after creation. (optional)
 	Queue bool `json:"queue,omitempty"`
 	// Custom URL with dispute information.
 	ReferenceURL string `json:"reference_url,omitempty"`
+	// A unique key to safely retry the request without creating the dispute twice. (optional)
+	IdempotencyKey string `json:"-"`
 	// Optional http client for the request. Typically needed when using App Engine.
 	OptHTTPClient *http.Client `json:"-"`
 }
 
+// Leaves out dates that are not set, rather than sending them as null, as
+// when they were strings.
+func (params CreateDisputeParams) MarshalJSON() ([]byte, error) {
+	type createDisputeParams CreateDisputeParams
+	return json.Marshal(struct {
+		createDisputeParams
+		ChargedAt  *Timestamp `json:"charged_at,omitempty"`
+		DisputedAt *Timestamp `json:"disputed_at,omitempty"`
+		DueBy      *Timestamp `json:"due_by,omitempty"`
+	}{
+		createDisputeParams: createDisputeParams(params),
+		ChargedAt:           optionalTimestamp(params.ChargedAt),
+		DisputedAt:          optionalTimestamp(params.DisputedAt),
+		DueBy:               optionalTimestamp(params.DueBy),
+	})
+}
+
+// Returns nil for the zero timestamp, so it is left out with omitempty.
+func optionalTimestamp(t Timestamp) *Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 type updateDisputeBody struct {
 	Template       string                 `json:"template,omitempty"`
 	Charge         string                 `json:"charge,omitempty"`
@@ -293,15 +360,29 @@ type updateDisputeBody struct {
 
 // Create a dispute
 func (dp *Disputes) Create(params *CreateDisputeParams) (*Dispute, error) {
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(params)
+	return dp.CreateWithContext(context.Background(), params)
+}
+
+// Create a dispute using the provided context for cancellation and deadlines.
+func (dp *Disputes) CreateWithContext(ctx context.Context, params *CreateDisputeParams) (*Dispute, error) {
+	if dp.client.ValidateParams {
+		if err := params.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	bodyJSON, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
 
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"POST",
 		"disputes",
-		b,
+		bodyJSON,
 		nil, // no query params
 	)
 
@@ -309,6 +390,8 @@ func (dp *Disputes) Create(params *CreateDisputeParams) (*Dispute, error) {
 		return nil, err
 	}
 
+	req.idempotencyKey = params.IdempotencyKey
+
 	var v Dispute
 	res, err := req.newRequest(&v)
 	if err == nil {
@@ -320,7 +403,13 @@ func (dp *Disputes) Create(params *CreateDisputeParams) (*Dispute, error) {
 
 // Retrieve a single disputes.
 func (dp *Disputes) Retrieve(params *RetrieveDisputeParams) (*Dispute, error) {
+	return dp.RetrieveWithContext(context.Background(), params)
+}
+
+// Retrieve a single disputes using the provided context for cancellation and deadlines.
+func (dp *Disputes) RetrieveWithContext(ctx context.Context, params *RetrieveDisputeParams) (*Dispute, error) {
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"GET",
@@ -344,7 +433,13 @@ func (dp *Disputes) Retrieve(params *RetrieveDisputeParams) (*Dispute, error) {
 
 // Retrieve the response for a dispute.
 func (dp *Disputes) Response(params *RetrieveDisputeParams) (*Response, error) {
+	return dp.ResponseWithContext(context.Background(), params)
+}
+
+// Retrieve the response for a dispute using the provided context for cancellation and deadlines.
+func (dp *Disputes) ResponseWithContext(ctx context.Context, params *RetrieveDisputeParams) (*Response, error) {
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"GET",
@@ -366,27 +461,77 @@ func (dp *Disputes) Response(params *RetrieveDisputeParams) (*Response, error) {
 	return &v, err
 }
 
+// Download the response document submitted for a dispute, e.g. a PDF, and
+// stream it to w. See Files.Download.
+func (dp *Disputes) DownloadResponse(params *RetrieveDisputeParams, w io.Writer) (*Download, error) {
+	return dp.DownloadResponseWithContext(context.Background(), params, w)
+}
+
+// Download the response document using the provided context for cancellation
+// and deadlines.
+func (dp *Disputes) DownloadResponseWithContext(ctx context.Context, params *RetrieveDisputeParams, w io.Writer) (*Download, error) {
+	response, err := dp.ResponseWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ResponseURL == "" {
+		return nil, fmt.Errorf("chargehound: dispute %s has no response document", params.ID)
+	}
+
+	files := &Files{client: dp.client}
+	return files.DownloadWithContext(ctx, &DownloadFileParams{
+		URL:           response.ResponseURL,
+		OptHTTPClient: params.OptHTTPClient,
+	}, w)
+}
+
 // Retrieve a list of disputes.
 func (dp *Disputes) List(params *ListDisputesParams) (*DisputeList, error) {
-	// map the query params to a dict
-	q := url.Values{}
-	if params.Limit > 0 {
-		q.Set("limit", strconv.Itoa(params.Limit))
-	}
+	return dp.ListWithContext(context.Background(), params)
+}
 
-	if params.StartingAfter != "" {
-		q.Set("starting_after", params.StartingAfter)
-	} else if params.EndingBefore != "" {
-		q.Set("ending_before", params.EndingBefore)
+// Retrieve a list of disputes using the provided context for cancellation and deadlines.
+func (dp *Disputes) ListWithContext(ctx context.Context, params *ListDisputesParams) (*DisputeList, error) {
+	q := newListDisputesQuery(params)
+
+	req, err := newAPIRequestor(
+		ctx,
+		dp.client,
+		params.OptHTTPClient,
+		"GET",
+		"disputes",
+		nil, // no body json
+		&q,
+	)
+
+	if err != nil {
+		return nil, err
 	}
 
-	if params.State != nil {
-		for _, state := range params.State {
-			q.Add("state", state)
-		}
+	var v DisputeList
+	res, err := req.newRequest(&v)
+	if err == nil {
+		v.Response = HTTPResponse{Status: res.StatusCode}
 	}
 
+	return &v, err
+}
+
+// Retrieve a list of disputes, calling fn with each dispute as soon as it is
+// decoded rather than holding the whole page in memory. The returned list has
+// the page details but no Data. An error returned by fn stops the request and
+// is returned as is.
+func (dp *Disputes) ListStream(params *ListDisputesParams, fn func(Dispute) error) (*DisputeList, error) {
+	return dp.ListStreamWithContext(context.Background(), params, fn)
+}
+
+// Retrieve a list of disputes one dispute at a time using the provided context for cancellation and deadlines.
+func (dp *Disputes) ListStreamWithContext(ctx context.Context, params *ListDisputesParams, fn func(Dispute) error) (*DisputeList, error) {
+	q := newListDisputesQuery(params)
+
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"GET",
@@ -400,7 +545,17 @@ func (dp *Disputes) List(params *ListDisputesParams) (*DisputeList, error) {
 	}
 
 	var v DisputeList
-	res, err := req.newRequest(&v)
+	var fnErr error
+	res, err := req.newStreamRequest(func(decoder *json.Decoder) error {
+		return decodeDisputeListStream(decoder, &v, func(d Dispute) error {
+			fnErr = fn(d)
+			return fnErr
+		})
+	})
+	if fnErr != nil {
+		return nil, fnErr
+	}
+
 	if err == nil {
 		v.Response = HTTPResponse{Status: res.StatusCode}
 	}
@@ -408,7 +563,53 @@ func (dp *Disputes) List(params *ListDisputesParams) (*DisputeList, error) {
 	return &v, err
 }
 
-func newUpdateDisputeBody(params *UpdateDisputeParams) (io.Reader, error) {
+func newListDisputesQuery(params *ListDisputesParams) url.Values {
+	// map the query params to a dict
+	q := url.Values{}
+	if params.Limit > 0 {
+		q.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	if params.StartingAfter != "" {
+		q.Set("starting_after", params.StartingAfter)
+	} else if params.EndingBefore != "" {
+		q.Set("ending_before", params.EndingBefore)
+	}
+
+	if params.State != nil {
+		for _, state := range params.State {
+			q.Add("state", string(state))
+		}
+	}
+
+	params.Created.encode(q, "created")
+	params.DisputedAt.encode(q, "disputed_at")
+	params.DueBy.encode(q, "due_by")
+
+	for _, reason := range params.Reason {
+		q.Add("reason", string(reason))
+	}
+
+	for _, kind := range params.Kind {
+		q.Add("kind", string(kind))
+	}
+
+	for _, processor := range params.Processor {
+		q.Add("processor", string(processor))
+	}
+
+	for _, account := range params.Account {
+		q.Add("account", account)
+	}
+
+	for _, currency := range params.Currency {
+		q.Add("currency", currency)
+	}
+
+	return q
+}
+
+func newUpdateDisputeBody(params *UpdateDisputeParams) ([]byte, error) {
 	body := updateDisputeBody{
 		Fields:         params.Fields,
 		Products:       params.Products,
@@ -424,20 +625,33 @@ func newUpdateDisputeBody(params *UpdateDisputeParams) (io.Reader, error) {
 		Charge:         params.Charge,
 	}
 
-	b := new(bytes.Buffer)
-	json.NewEncoder(b).Encode(body)
+	if len(params.ForceSendFields) == 0 && len(params.NullFields) == 0 {
+		return json.Marshal(body)
+	}
 
-	return b, nil
+	return marshalUpdateFields(body, params.ForceSendFields, params.NullFields)
 }
 
 // Update a dispute.
 func (dp *Disputes) Update(params *UpdateDisputeParams) (*Dispute, error) {
+	return dp.UpdateWithContext(context.Background(), params)
+}
+
+// Update a dispute using the provided context for cancellation and deadlines.
+func (dp *Disputes) UpdateWithContext(ctx context.Context, params *UpdateDisputeParams) (*Dispute, error) {
+	if dp.client.ValidateParams {
+		if err := params.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	bodyJSON, err := newUpdateDisputeBody(params)
 	if err != nil {
 		return nil, err
 	}
 
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"PUT",
@@ -461,12 +675,24 @@ func (dp *Disputes) Update(params *UpdateDisputeParams) (*Dispute, error) {
 
 // Submit a dispute.
 func (dp *Disputes) Submit(params *UpdateDisputeParams) (*Dispute, error) {
+	return dp.SubmitWithContext(context.Background(), params)
+}
+
+// Submit a dispute using the provided context for cancellation and deadlines.
+func (dp *Disputes) SubmitWithContext(ctx context.Context, params *UpdateDisputeParams) (*Dispute, error) {
+	if dp.client.ValidateParams {
+		if err := params.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	bodyJSON, err := newUpdateDisputeBody(params)
 	if err != nil {
 		return nil, err
 	}
 
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"POST",
@@ -479,6 +705,8 @@ func (dp *Disputes) Submit(params *UpdateDisputeParams) (*Dispute, error) {
 		return nil, err
 	}
 
+	req.idempotencyKey = params.IdempotencyKey
+
 	var v Dispute
 	res, err := req.newRequest(&v)
 	if err == nil {
@@ -490,7 +718,13 @@ func (dp *Disputes) Submit(params *UpdateDisputeParams) (*Dispute, error) {
 
 // Accept a dispute.
 func (dp *Disputes) Accept(params *AcceptDisputeParams) (*Dispute, error) {
+	return dp.AcceptWithContext(context.Background(), params)
+}
+
+// Accept a dispute using the provided context for cancellation and deadlines.
+func (dp *Disputes) AcceptWithContext(ctx context.Context, params *AcceptDisputeParams) (*Dispute, error) {
 	req, err := newAPIRequestor(
+		ctx,
 		dp.client,
 		params.OptHTTPClient,
 		"POST",
@@ -503,6 +737,8 @@ func (dp *Disputes) Accept(params *AcceptDisputeParams) (*Dispute, error) {
 		return nil, err
 	}
 
+	req.idempotencyKey = params.IdempotencyKey
+
 	var v Dispute
 	res, err := req.newRequest(&v)
 	if err == nil {
@@ -1,13 +1,16 @@
 package chargehound_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
-	"github.com/chargehound/chargehound-go/v8.6.2"
+	"github.com/chargehound/chargehound-go/v9"
 )
 
 func TestRetrieveDispute(t *testing.T) {
@@ -227,7 +230,7 @@ func TestListDisputesFilterState(t *testing.T) {
 	ch.Protocol = url.Scheme + "://"
 
 	_, err = ch.Disputes.List(&chargehound.ListDisputesParams{
-		State: []string{"needs_response"},
+		State: []chargehound.DisputeState{chargehound.DisputeStateNeedsResponse},
 	})
 	if err != nil {
 		t.Error(err)
@@ -271,7 +274,7 @@ func TestListDisputesFilterMultipleStates(t *testing.T) {
 	ch.Protocol = url.Scheme + "://"
 
 	_, err = ch.Disputes.List(&chargehound.ListDisputesParams{
-		State: []string{
+		State: []chargehound.DisputeState{
 			"needs_response",
 			"warning_needs_response",
 		},
@@ -969,3 +972,294 @@ func TestOverrideAPIVersion(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestRetrieveDisputeWithContext(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/disputes/dp_xxx" {
+			t.Error("Incorrect path.")
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	dispute, err := ch.Disputes.RetrieveWithContext(context.Background(), &chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if dispute.ID != "dp_xxx" {
+		t.Error("Incorrect dispute id.")
+	}
+}
+
+func TestContextDeadline(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = ch.Disputes.ListWithContext(ctx, &chargehound.ListDisputesParams{})
+	if err != context.DeadlineExceeded {
+		t.Error("Expected deadline exceeded, got: ", err)
+	}
+}
+
+func TestContextCanceled(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request sent with a canceled context.")
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ch.Disputes.AcceptWithContext(ctx, &chargehound.AcceptDisputeParams{ID: "dp_xxx"})
+	if err != context.Canceled {
+		t.Error("Expected context canceled, got: ", err)
+	}
+}
+
+func TestUpdateDisputeRedirect(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	redirected := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/disputes/dp_xxx" {
+			http.Redirect(w, r, "/v1/disputes/dp_xxx/moved", http.StatusTemporaryRedirect)
+			return
+		}
+
+		redirected = true
+
+		if r.Method != "PUT" {
+			t.Error("Incorrect method.")
+		}
+
+		decoder := json.NewDecoder(r.Body)
+		b := make(map[string]interface{})
+		err := decoder.Decode(&b)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if b["template"] != "tmpl_1" {
+			t.Error("Incorrect template id.")
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Update(&chargehound.UpdateDisputeParams{
+		ID:       "dp_xxx",
+		Template: "tmpl_1",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !redirected {
+		t.Error("Redirect not followed.")
+	}
+}
+
+func TestListDisputesFilters(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/disputes" {
+			t.Error("Incorrect path.")
+		}
+
+		expected := "account=acct_1&created%5Bgte%5D=2024-01-01T00%3A00%3A00Z&created%5Blt%5D=2024-02-01T00%3A00%3A00Z" +
+			"&currency=usd&kind=chargeback&processor=stripe&reason=fraudulent&reason=unrecognized"
+		if r.URL.RawQuery != expected {
+			t.Error("Incorrect query: ", r.URL.RawQuery)
+		}
+
+		json.NewEncoder(w).Encode(chargehound.DisputeList{})
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.List(&chargehound.ListDisputesParams{
+		Created: &chargehound.DateRange{
+			From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Reason:    []chargehound.DisputeReason{"fraudulent", "unrecognized"},
+		Kind:      []chargehound.DisputeKind{"chargeback"},
+		Processor: []chargehound.Processor{"stripe"},
+		Account:   []string{"acct_1"},
+		Currency:  []string{"usd"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateDisputeTimestamps(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decoder := json.NewDecoder(r.Body)
+		b := make(map[string]interface{})
+		err := decoder.Decode(&b)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if b["charged_at"] != "2016-10-01T22:20:53Z" {
+			t.Error("Incorrect charged at.")
+		}
+
+		if b["id"] != "dp_xxx" {
+			t.Error("Incorrect id.")
+		}
+
+		// Dates that are not set are left out rather than sent as null.
+		for _, key := range []string{"disputed_at", "due_by"} {
+			if _, ok := b[key]; ok {
+				t.Error("Expected no ", key, ", got: ", b[key])
+			}
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Create(&chargehound.CreateDisputeParams{
+		ID:        "dp_xxx",
+		ChargedAt: chargehound.Timestamp{Time: time.Date(2016, 10, 1, 22, 20, 53, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateDisputeClearFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := make(map[string]json.RawMessage)
+		err := json.NewDecoder(r.Body).Decode(&b)
+		if err != nil {
+			t.Error(err)
+		}
+
+		expected := map[string]string{
+			"template":      `"tmpl_1"`,
+			"force":         `false`,
+			"products":      `[]`,
+			"reference_url": `null`,
+			"fields":        `null`,
+		}
+
+		if len(b) != len(expected) {
+			t.Error("Incorrect body: ", b)
+		}
+
+		for key, value := range expected {
+			if string(b[key]) != value {
+				t.Error("Incorrect ", key, ": ", string(b[key]))
+			}
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	_, err := ch.Disputes.Update(&chargehound.UpdateDisputeParams{
+		ID:              "dp_xxx",
+		Template:        "tmpl_1",
+		ForceSendFields: []chargehound.UpdateField{chargehound.UpdateFieldForce, chargehound.UpdateFieldProducts},
+		NullFields:      []chargehound.UpdateField{chargehound.UpdateFieldReferenceURL, chargehound.UpdateFieldFields},
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdateDisputeUnknownClearField(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	_, err := ch.Disputes.Update(&chargehound.UpdateDisputeParams{
+		ID:         "dp_xxx",
+		NullFields: []chargehound.UpdateField{"IdempotencyKey"},
+	})
+	if !errors.Is(err, chargehound.ErrInvalidParams) {
+		t.Error("Expected error for unknown field, got: ", err)
+	}
+
+	_, err = ch.Disputes.Update(&chargehound.UpdateDisputeParams{
+		ID:              "dp_xxx",
+		ForceSendFields: []chargehound.UpdateField{chargehound.UpdateFieldTemplate},
+		NullFields:      []chargehound.UpdateField{chargehound.UpdateFieldTemplate},
+	})
+	if !errors.Is(err, chargehound.ErrInvalidParams) {
+		t.Error("Expected error for a field both forced and null, got: ", err)
+	}
+}
@@ -0,0 +1,171 @@
+package chargehound
+
+// The state of a dispute. Values the library doesn't know about yet are
+// decoded as is and are not valid according to IsValid.
+type DisputeState string
+
+const (
+	DisputeStateNeedsResponse        = DisputeState("needs_response")
+	DisputeStateSubmitted            = DisputeState("submitted")
+	DisputeStateUnderReview          = DisputeState("under_review")
+	DisputeStateWon                  = DisputeState("won")
+	DisputeStateLost                 = DisputeState("lost")
+	DisputeStateWarningNeedsResponse = DisputeState("warning_needs_response")
+	DisputeStateWarningUnderReview   = DisputeState("warning_under_review")
+	DisputeStateWarningClosed        = DisputeState("warning_closed")
+	DisputeStateResponseDisabled     = DisputeState("response_disabled")
+	DisputeStateChargeRefunded       = DisputeState("charge_refunded")
+	DisputeStateAccepted             = DisputeState("accepted")
+	DisputeStateQueued               = DisputeState("queued")
+)
+
+// Reports whether the state is one of the known dispute states.
+func (s DisputeState) IsValid() bool {
+	switch s {
+	case DisputeStateNeedsResponse, DisputeStateSubmitted, DisputeStateUnderReview,
+		DisputeStateWon, DisputeStateLost, DisputeStateWarningNeedsResponse,
+		DisputeStateWarningUnderReview, DisputeStateWarningClosed,
+		DisputeStateResponseDisabled, DisputeStateChargeRefunded,
+		DisputeStateAccepted, DisputeStateQueued:
+		return true
+	}
+	return false
+}
+
+func (s DisputeState) String() string {
+	return string(s)
+}
+
+// The bank provided reason for a dispute. Values the library doesn't know
+// about yet are decoded as is and are not valid according to IsValid.
+type DisputeReason string
+
+const (
+	DisputeReasonFraudulent                     = DisputeReason("fraudulent")
+	DisputeReasonUnrecognized                   = DisputeReason("unrecognized")
+	DisputeReasonGeneral                        = DisputeReason("general")
+	DisputeReasonDuplicate                      = DisputeReason("duplicate")
+	DisputeReasonSubscriptionCanceled           = DisputeReason("subscription_canceled")
+	DisputeReasonProductUnacceptable            = DisputeReason("product_unacceptable")
+	DisputeReasonProductNotReceived             = DisputeReason("product_not_received")
+	DisputeReasonCreditNotProcessed             = DisputeReason("credit_not_processed")
+	DisputeReasonIncorrectAccountDetails        = DisputeReason("incorrect_account_details")
+	DisputeReasonInsufficientFunds              = DisputeReason("insufficient_funds")
+	DisputeReasonBankCannotProcess              = DisputeReason("bank_cannot_process")
+	DisputeReasonDebitNotAuthorized             = DisputeReason("debit_not_authorized")
+	DisputeReasonGoodsServicesReturnedOrRefused = DisputeReason("goods_services_returned_or_refused")
+	DisputeReasonGoodsServicesCancelled         = DisputeReason("goods_services_cancelled")
+)
+
+// Reports whether the reason is one of the known dispute reasons.
+func (r DisputeReason) IsValid() bool {
+	switch r {
+	case DisputeReasonFraudulent, DisputeReasonUnrecognized, DisputeReasonGeneral,
+		DisputeReasonDuplicate, DisputeReasonSubscriptionCanceled,
+		DisputeReasonProductUnacceptable, DisputeReasonProductNotReceived,
+		DisputeReasonCreditNotProcessed, DisputeReasonIncorrectAccountDetails,
+		DisputeReasonInsufficientFunds, DisputeReasonBankCannotProcess,
+		DisputeReasonDebitNotAuthorized, DisputeReasonGoodsServicesReturnedOrRefused,
+		DisputeReasonGoodsServicesCancelled:
+		return true
+	}
+	return false
+}
+
+func (r DisputeReason) String() string {
+	return string(r)
+}
+
+// The kind of a dispute. Values the library doesn't know about yet are
+// decoded as is and are not valid according to IsValid.
+type DisputeKind string
+
+const (
+	DisputeKindChargeback     = DisputeKind("chargeback")
+	DisputeKindRetrieval      = DisputeKind("retrieval")
+	DisputeKindPreArbitration = DisputeKind("pre_arbitration")
+)
+
+// Reports whether the kind is one of the known dispute kinds.
+func (k DisputeKind) IsValid() bool {
+	switch k {
+	case DisputeKindChargeback, DisputeKindRetrieval, DisputeKindPreArbitration:
+		return true
+	}
+	return false
+}
+
+func (k DisputeKind) String() string {
+	return string(k)
+}
+
+// The payment processor of a dispute. Values the library doesn't know about
+// yet are decoded as is and are not valid according to IsValid.
+type Processor string
+
+const (
+	ProcessorBraintree = Processor("braintree")
+	ProcessorStripe    = Processor("stripe")
+)
+
+// Reports whether the processor is one of the known payment processors.
+func (p Processor) IsValid() bool {
+	switch p {
+	case ProcessorBraintree, ProcessorStripe:
+		return true
+	}
+	return false
+}
+
+func (p Processor) String() string {
+	return string(p)
+}
+
+// The source of a dispute. Values the library doesn't know about yet are
+// decoded as is and are not valid according to IsValid.
+type DisputeSource string
+
+const (
+	DisputeSourceMock      = DisputeSource("mock")
+	DisputeSourceBraintree = DisputeSource("braintree")
+	DisputeSourceAPI       = DisputeSource("api")
+	DisputeSourceStripe    = DisputeSource("stripe")
+)
+
+// Reports whether the source is one of the known dispute sources.
+func (s DisputeSource) IsValid() bool {
+	switch s {
+	case DisputeSourceMock, DisputeSourceBraintree, DisputeSourceAPI, DisputeSourceStripe:
+		return true
+	}
+	return false
+}
+
+func (s DisputeSource) String() string {
+	return string(s)
+}
+
+// The result of a cvc or address check on the disputed charge. Values the
+// library doesn't know about yet are decoded as is and are not valid
+// according to IsValid.
+type CheckResult string
+
+const (
+	CheckResultPass        = CheckResult("pass")
+	CheckResultFail        = CheckResult("fail")
+	CheckResultUnavailable = CheckResult("unavailable")
+	CheckResultChecked     = CheckResult("checked")
+)
+
+// Reports whether the result is one of the known check results.
+func (c CheckResult) IsValid() bool {
+	switch c {
+	case CheckResultPass, CheckResultFail, CheckResultUnavailable, CheckResultChecked:
+		return true
+	}
+	return false
+}
+
+func (c CheckResult) String() string {
+	return string(c)
+}
@@ -0,0 +1,79 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func TestEnumsIsValid(t *testing.T) {
+	if !chargehound.DisputeStateNeedsResponse.IsValid() || chargehound.DisputeState("needs-response").IsValid() {
+		t.Error("Incorrect state validation.")
+	}
+
+	if !chargehound.DisputeReasonGoodsServicesCancelled.IsValid() || chargehound.DisputeReason("").IsValid() {
+		t.Error("Incorrect reason validation.")
+	}
+
+	if !chargehound.DisputeKindPreArbitration.IsValid() || chargehound.DisputeKind("arbitration").IsValid() {
+		t.Error("Incorrect kind validation.")
+	}
+
+	if !chargehound.ProcessorBraintree.IsValid() || chargehound.Processor("paypal").IsValid() {
+		t.Error("Incorrect processor validation.")
+	}
+
+	if !chargehound.DisputeSourceAPI.IsValid() || chargehound.DisputeSource("csv").IsValid() {
+		t.Error("Incorrect source validation.")
+	}
+
+	if !chargehound.CheckResultUnavailable.IsValid() || chargehound.CheckResult("passed").IsValid() {
+		t.Error("Incorrect check result validation.")
+	}
+}
+
+func TestEnumsString(t *testing.T) {
+	if chargehound.DisputeStateWarningNeedsResponse.String() != "warning_needs_response" {
+		t.Error("Incorrect state string.")
+	}
+
+	if chargehound.DisputeReasonFraudulent.String() != "fraudulent" {
+		t.Error("Incorrect reason string.")
+	}
+}
+
+func TestEnumsDecodeUnknownValues(t *testing.T) {
+	var dispute chargehound.Dispute
+	err := json.Unmarshal([]byte(`{
+		"state": "escalated",
+		"reason": "fraudulent",
+		"kind": null,
+		"processor": "adyen",
+		"source": "stripe",
+		"cvc_check": "pass"
+	}`), &dispute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dispute.State != "escalated" || dispute.State.IsValid() {
+		t.Error("Incorrect unknown state: ", dispute.State)
+	}
+
+	if dispute.Reason != chargehound.DisputeReasonFraudulent {
+		t.Error("Incorrect reason: ", dispute.Reason)
+	}
+
+	if dispute.Kind != "" {
+		t.Error("Incorrect kind: ", dispute.Kind)
+	}
+
+	if dispute.Processor != "adyen" || dispute.Processor.IsValid() {
+		t.Error("Incorrect unknown processor: ", dispute.Processor)
+	}
+
+	if dispute.Source != chargehound.DisputeSourceStripe || dispute.CVCCheck != chargehound.CheckResultPass {
+		t.Error("Incorrect source or check: ", dispute.Source, dispute.CVCCheck)
+	}
+}
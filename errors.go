@@ -1,21 +1,68 @@
 package chargehound
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
+	"syscall"
+	"time"
 )
 
 type ErrorType string
 
 const (
-	BadRequestError     = ErrorType("Bad Request")
-	UnauthorizedError   = ErrorType("Unauthorized")
-	ForbiddenError      = ErrorType("Forbidden")
-	NotFoundError       = ErrorType("Not Found")
-	InternalServerError = ErrorType("Server Error")
-	GenericError        = ErrorType("Error")
+	BadRequestError          = ErrorType("Bad Request")
+	UnauthorizedError        = ErrorType("Unauthorized")
+	ForbiddenError           = ErrorType("Forbidden")
+	NotFoundError            = ErrorType("Not Found")
+	ConflictError            = ErrorType("Conflict")
+	UnprocessableEntityError = ErrorType("Unprocessable Entity")
+	TooManyRequestsError     = ErrorType("Too Many Requests")
+	InternalServerError      = ErrorType("Server Error")
+	BadGatewayError          = ErrorType("Bad Gateway")
+	ServiceUnavailableError  = ErrorType("Service Unavailable")
+	GatewayTimeoutError      = ErrorType("Gateway Timeout")
+	GenericError             = ErrorType("Error")
 )
 
+// Sentinel errors matched by API errors of the corresponding type with
+// errors.Is, e.g. errors.Is(err, chargehound.ErrNotFound).
+var (
+	ErrBadRequest          = errors.New("chargehound: bad request")
+	ErrUnauthorized        = errors.New("chargehound: unauthorized")
+	ErrForbidden           = errors.New("chargehound: forbidden")
+	ErrNotFound            = errors.New("chargehound: not found")
+	ErrConflict            = errors.New("chargehound: conflict")
+	ErrUnprocessableEntity = errors.New("chargehound: unprocessable entity")
+	ErrRateLimited         = errors.New("chargehound: rate limited")
+	ErrServer              = errors.New("chargehound: server error")
+	ErrBadGateway          = errors.New("chargehound: bad gateway")
+	ErrServiceUnavailable  = errors.New("chargehound: service unavailable")
+	ErrGatewayTimeout      = errors.New("chargehound: gateway timeout")
+	// Returned when a response body is larger than the client's MaxResponseBytes.
+	ErrResponseTooLarge = errors.New("chargehound: response body too large")
+)
+
+var errorTypeSentinels = map[ErrorType]error{
+	BadRequestError:          ErrBadRequest,
+	UnauthorizedError:        ErrUnauthorized,
+	ForbiddenError:           ErrForbidden,
+	NotFoundError:            ErrNotFound,
+	ConflictError:            ErrConflict,
+	UnprocessableEntityError: ErrUnprocessableEntity,
+	TooManyRequestsError:     ErrRateLimited,
+	InternalServerError:      ErrServer,
+	BadGatewayError:          ErrBadGateway,
+	ServiceUnavailableError:  ErrServiceUnavailable,
+	GatewayTimeoutError:      ErrGatewayTimeout,
+}
+
 // A Chargehound API error
 type Error interface {
 	// The error message
@@ -28,11 +75,82 @@ type Error interface {
 	ApiErrorType() string
 }
 
+// Details of the response behind a Chargehound API error. All API errors
+// returned by the client implement it, find them with errors.As.
+type ErrorDetails interface {
+	// How long to wait before retrying, from the Retry-After header. Zero if
+	// the API did not send one.
+	RetryAfter() time.Duration
+	// The URL of the failed request
+	URL() string
+	// Whether the request was made in live mode
+	Livemode() bool
+	// The id the API assigned to the request, to include in support requests
+	RequestID() string
+	// The HTTP response headers
+	Header() http.Header
+}
+
+// Response headers that may carry the id the API assigned to a request.
+var requestIDHeaders = []string{"Request-Id", "X-Request-Id"}
+
+// The error returned when the API rate limit is exceeded (HTTP 429). Use
+// RetryAfter to find out how long to back off.
+type RateLimitError struct {
+	errorResponse
+}
+
+// The error returned when a request could not be sent or its response could
+// not be read, e.g. a connection reset or timeout. The underlying error is
+// available with errors.Unwrap.
+type TransportError struct {
+	// The HTTP method of the request.
+	Method string
+	// The URL path of the request.
+	Path string
+	// The underlying error.
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("chargehound: %s %s: %v", e.Method, e.Path, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// The error returned when a response body could not be decoded. The
+// underlying error is available with errors.Unwrap.
+type DecodeError struct {
+	// The HTTP method of the request.
+	Method string
+	// The URL path of the request.
+	Path string
+	// The HTTP status code of the response.
+	Status int
+	// The underlying error.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("chargehound: %s %s: decoding %d response: %v", e.Method, e.Path, e.Status, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
 type errorResponse struct {
-	Status    int
-	ErrorType ErrorType
-	Message   string
-	ApiType   string `json:"type"`
+	Status     int
+	ErrorType  ErrorType
+	Message    string
+	ApiType    string `json:"type"`
+	retryAfter time.Duration
+	url        string
+	livemode   bool
+	requestID  string
+	header     http.Header
 }
 
 type errorJSON struct {
@@ -41,15 +159,50 @@ type errorJSON struct {
 	Error    errorResponse
 }
 
+// The maximum number of bytes of an error response body that are read.
+const maxErrorBodyBytes = 64 << 10
+
+// The maximum length of a raw response body included in an error message.
+const maxErrorMessageBody = 256
+
 func responseToError(res *http.Response) error {
+	// Read errors are ignored: whatever part of the body was read is still
+	// useful, and the status code is always known.
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodyBytes))
+
 	var errRes errorJSON
-	decoder := json.NewDecoder(res.Body)
-	err := decoder.Decode(&errRes)
-	if err != nil {
-		return err
+	err := json.Unmarshal(body, &errRes)
+	if err != nil || (errRes.Error.Message == "" && errRes.Error.ApiType == "") {
+		// Not the expected error shape, e.g. an HTML page from a proxy or an
+		// empty body. Fall back to the status line and the raw body.
+		errRes = errorJSON{}
+		errRes.Error.Message = res.Status
+		if raw := truncateErrorBody(body); raw != "" {
+			errRes.Error.Message += ": " + raw
+		}
+	}
+
+	errRes.Error.Status = res.StatusCode
+	errRes.Error.livemode = errRes.Livemode
+	errRes.Error.header = res.Header
+
+	errRes.Error.url = errRes.Url
+	if errRes.Error.url == "" && res.Request != nil {
+		errRes.Error.url = res.Request.URL.String()
 	}
 
-	switch errRes.Error.Status {
+	for _, h := range requestIDHeaders {
+		if id := res.Header.Get(h); id != "" {
+			errRes.Error.requestID = id
+			break
+		}
+	}
+
+	if retryAfter, ok := parseRetryAfter(res); ok {
+		errRes.Error.retryAfter = retryAfter
+	}
+
+	switch res.StatusCode {
 	case 400:
 		errRes.Error.ErrorType = BadRequestError
 	case 401:
@@ -58,8 +211,21 @@ func responseToError(res *http.Response) error {
 		errRes.Error.ErrorType = ForbiddenError
 	case 404:
 		errRes.Error.ErrorType = NotFoundError
+	case 409:
+		errRes.Error.ErrorType = ConflictError
+	case 422:
+		errRes.Error.ErrorType = UnprocessableEntityError
+	case 429:
+		errRes.Error.ErrorType = TooManyRequestsError
+		return &RateLimitError{errRes.Error}
 	case 500:
 		errRes.Error.ErrorType = InternalServerError
+	case 502:
+		errRes.Error.ErrorType = BadGatewayError
+	case 503:
+		errRes.Error.ErrorType = ServiceUnavailableError
+	case 504:
+		errRes.Error.ErrorType = GatewayTimeoutError
 	default:
 		errRes.Error.ErrorType = GenericError
 	}
@@ -67,6 +233,26 @@ func responseToError(res *http.Response) error {
 	return &errRes.Error
 }
 
+// Returns the body as a single trimmed line, truncated to a readable length.
+func truncateErrorBody(body []byte) string {
+	raw := strings.Join(strings.Fields(string(body)), " ")
+	if len(raw) <= maxErrorMessageBody {
+		return raw
+	}
+
+	// Drop a rune cut in half, along with any other invalid bytes.
+	return strings.ToValidUTF8(raw[:maxErrorMessageBody], "") + "..."
+}
+
+func newDecodeError(res *http.Response, err error) error {
+	return &DecodeError{
+		Method: res.Request.Method,
+		Path:   res.Request.URL.Path,
+		Status: res.StatusCode,
+		Err:    err,
+	}
+}
+
 func (e *errorResponse) Error() string {
 	return string(e.ErrorType) + ": " + e.Message
 }
@@ -82,3 +268,118 @@ func (e *errorResponse) Type() ErrorType {
 func (e *errorResponse) ApiErrorType() string {
 	return e.ApiType
 }
+
+func (e *errorResponse) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+func (e *errorResponse) URL() string {
+	return e.url
+}
+
+func (e *errorResponse) Livemode() bool {
+	return e.livemode
+}
+
+func (e *errorResponse) RequestID() string {
+	return e.requestID
+}
+
+func (e *errorResponse) Header() http.Header {
+	return e.header
+}
+
+// Reports whether the target is the sentinel error for this error's type.
+func (e *errorResponse) Is(target error) bool {
+	sentinel, ok := errorTypeSentinels[e.ErrorType]
+	return ok && target == sentinel
+}
+
+// HTTP status codes of API errors that are expected to be transient.
+var temporaryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Reports whether err is a transient failure that may go away on its own:
+// rate limiting, server errors, timeouts and connection failures. Errors
+// caused by the request itself, such as a bad request or a missing dispute,
+// and canceled contexts are not temporary. A response that failed to decode
+// after the API accepted a non-idempotent request is not temporary either,
+// since sending it again could apply it twice.
+func IsTemporary(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) && decodeErr.Status < 400 && !isIdempotentMethod(decodeErr.Method) {
+		return false
+	}
+
+	var apiErr Error
+	if errors.As(err, &apiErr) {
+		for _, code := range temporaryStatusCodes {
+			if apiErr.StatusCode() == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var transportErr *TransportError
+	if errors.As(err, &transportErr) {
+		return isRetryableNetworkError(transportErr.Err)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	return isRetryableNetworkError(err)
+}
+
+// Reports whether a request that failed with err may succeed if it is sent
+// again. Errors from the caller's own context are not retryable, since a
+// retry with the same context would fail again. The built-in retry policy
+// uses the same classification.
+func IsRetryable(err error) bool {
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) &&
+		(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return false
+	}
+
+	return IsTemporary(err)
+}
+
+// Returns true if the request could not have reached the API because the
+// connection was never established.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isRetryableNetworkError(err error) bool {
+	if isDialError(err) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
@@ -1,12 +1,20 @@
 package chargehound_test
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
+	"unicode/utf8"
 
-	"github.com/chargehound/chargehound-go/v8.6.2"
+	"github.com/chargehound/chargehound-go/v9"
 )
 
 var errorTests = []struct {
@@ -15,6 +23,7 @@ var errorTests = []struct {
 	errorType chargehound.ErrorType
 	apiType   string
 	message   string
+	sentinel  error
 }{
 	{
 		"{\"error\": { \"status\": 404, \"message\": \"A dispute with id 'puppy' was not found\"}}",
@@ -22,6 +31,7 @@ var errorTests = []struct {
 		chargehound.NotFoundError,
 		"",
 		"Not Found: A dispute with id 'puppy' was not found",
+		chargehound.ErrNotFound,
 	},
 	{
 		"{\"error\": { \"status\": 404, \"type\": \"dispute_not_found\", \"message\": \"A dispute with id 'puppy' was not found\"}}",
@@ -29,6 +39,7 @@ var errorTests = []struct {
 		chargehound.NotFoundError,
 		"dispute_not_found",
 		"Not Found: A dispute with id 'puppy' was not found",
+		chargehound.ErrNotFound,
 	},
 	{
 		"{\"error\": { \"status\": 400, \"message\": \"Wrong param\"}}",
@@ -36,6 +47,7 @@ var errorTests = []struct {
 		chargehound.BadRequestError,
 		"",
 		"Bad Request: Wrong param",
+		chargehound.ErrBadRequest,
 	},
 	{
 		"{\"error\": { \"status\": 401, \"message\": \"No user\"}}",
@@ -43,6 +55,7 @@ var errorTests = []struct {
 		chargehound.UnauthorizedError,
 		"",
 		"Unauthorized: No user",
+		chargehound.ErrUnauthorized,
 	},
 	{
 		"{\"error\": { \"status\": 403, \"message\": \"Wrong user\"}}",
@@ -50,6 +63,31 @@ var errorTests = []struct {
 		chargehound.ForbiddenError,
 		"",
 		"Forbidden: Wrong user",
+		chargehound.ErrForbidden,
+	},
+	{
+		"{\"error\": { \"status\": 409, \"message\": \"Already submitted\"}}",
+		409,
+		chargehound.ConflictError,
+		"",
+		"Conflict: Already submitted",
+		chargehound.ErrConflict,
+	},
+	{
+		"{\"error\": { \"status\": 422, \"message\": \"Missing fields\"}}",
+		422,
+		chargehound.UnprocessableEntityError,
+		"",
+		"Unprocessable Entity: Missing fields",
+		chargehound.ErrUnprocessableEntity,
+	},
+	{
+		"{\"error\": { \"status\": 429, \"message\": \"Slow down\"}}",
+		429,
+		chargehound.TooManyRequestsError,
+		"",
+		"Too Many Requests: Slow down",
+		chargehound.ErrRateLimited,
 	},
 	{
 		"{\"error\": { \"status\": 500, \"message\": \"Server error\"}}",
@@ -57,6 +95,31 @@ var errorTests = []struct {
 		chargehound.InternalServerError,
 		"",
 		"Server Error: Server error",
+		chargehound.ErrServer,
+	},
+	{
+		"{\"error\": { \"status\": 502, \"message\": \"Bad gateway\"}}",
+		502,
+		chargehound.BadGatewayError,
+		"",
+		"Bad Gateway: Bad gateway",
+		chargehound.ErrBadGateway,
+	},
+	{
+		"{\"error\": { \"status\": 503, \"message\": \"Unavailable\"}}",
+		503,
+		chargehound.ServiceUnavailableError,
+		"",
+		"Service Unavailable: Unavailable",
+		chargehound.ErrServiceUnavailable,
+	},
+	{
+		"{\"error\": { \"status\": 504, \"message\": \"Timeout\"}}",
+		504,
+		chargehound.GatewayTimeoutError,
+		"",
+		"Gateway Timeout: Timeout",
+		chargehound.ErrGatewayTimeout,
 	},
 }
 
@@ -69,6 +132,7 @@ func TestErrors(t *testing.T) {
 		errorType := test.errorType
 		apiType := test.apiType
 		message := test.message
+		sentinel := test.sentinel
 
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, error, code)
@@ -105,5 +169,393 @@ func TestErrors(t *testing.T) {
 		if apiType != chErr.ApiErrorType() {
 			t.Error("Expected error type: ", apiType)
 		}
+
+		if !errors.Is(err, sentinel) {
+			t.Error("Expected errors.Is to match: ", sentinel)
+		}
+
+		if errors.Is(err, chargehound.ErrResponseTooLarge) {
+			t.Error("Unexpected errors.Is match.")
+		}
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "{\"error\": { \"status\": 429, \"message\": \"Slow down\"}}", 429)
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+
+	rateLimitErr, ok := err.(*chargehound.RateLimitError)
+	if !ok {
+		t.Fatal("Expected a rate limit error, got: ", err)
+	}
+
+	if rateLimitErr.RetryAfter() != 30*time.Second {
+		t.Error("Incorrect retry after: ", rateLimitErr.RetryAfter())
+	}
+
+	chErr := err.(chargehound.Error)
+
+	if chErr.Type() != chargehound.TooManyRequestsError {
+		t.Error("Incorrect error type: ", chErr.Type())
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+	ts.Close()
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+
+	var transportErr *chargehound.TransportError
+	if !errors.As(err, &transportErr) {
+		t.Fatal("Expected a transport error, got: ", err)
+	}
+
+	if transportErr.Method != "GET" || transportErr.Path != "/v1/disputes/dp_xxx" {
+		t.Error("Incorrect request: ", transportErr.Method, transportErr.Path)
+	}
+
+	if transportErr.Unwrap() == nil {
+		t.Error("Missing underlying error.")
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"id\": "))
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+
+	var decodeErr *chargehound.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatal("Expected a decode error, got: ", err)
+	}
+
+	if decodeErr.Status != 200 || decodeErr.Path != "/v1/disputes/dp_xxx" {
+		t.Error("Incorrect response: ", decodeErr.Status, decodeErr.Path)
+	}
+}
+
+func TestDecodeErrorAfterSubmitNotRetryable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("{\"id\": "))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	_, err := ch.Disputes.Submit(&chargehound.UpdateDisputeParams{ID: "dp_xxx"})
+
+	var decodeErr *chargehound.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatal("Expected a decode error, got: ", err)
+	}
+
+	if chargehound.IsRetryable(err) || chargehound.IsTemporary(err) {
+		t.Error("Submit that the API accepted should not be retryable: ", err)
+	}
+}
+
+var fallbackErrorTests = []struct {
+	body      string
+	code      int
+	errorType chargehound.ErrorType
+	message   string
+}{
+	{
+		"<html><body>\n<h1>502 Bad Gateway</h1>\n</body></html>",
+		502,
+		chargehound.BadGatewayError,
+		"Bad Gateway: 502 Bad Gateway: <html><body> <h1>502 Bad Gateway</h1> </body></html>",
+	},
+	{
+		"",
+		503,
+		chargehound.ServiceUnavailableError,
+		"Service Unavailable: 503 Service Unavailable",
+	},
+	{
+		"{\"message\": \"unexpected shape\"}",
+		500,
+		chargehound.InternalServerError,
+		"Server Error: 500 Internal Server Error: {\"message\": \"unexpected shape\"}",
+	},
+	{
+		"{\"error\": { \"status\": 400, \"message\": \"Missing fields\"}}",
+		422,
+		chargehound.UnprocessableEntityError,
+		"Unprocessable Entity: Missing fields",
+	},
+}
+
+func TestFallbackErrors(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	for _, test := range fallbackErrorTests {
+		body := test.body
+		code := test.code
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(code)
+			w.Write([]byte(body))
+		}))
+		defer ts.Close()
+
+		url, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Error(err)
+		}
+
+		ch.Host = url.Host
+		ch.Protocol = url.Scheme + "://"
+
+		_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+
+		chErr, ok := err.(chargehound.Error)
+		if !ok {
+			t.Fatal("Expected an API error, got: ", err)
+		}
+
+		if chErr.StatusCode() != code {
+			t.Error("Expected status: ", code)
+		}
+
+		if chErr.Type() != test.errorType {
+			t.Error("Expected type: ", test.errorType)
+		}
+
+		if chErr.Error() != test.message {
+			t.Error("Expected error: ", test.message, ", got: ", chErr.Error())
+		}
+	}
+}
+
+func TestFallbackErrorTruncated(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(502)
+		w.Write([]byte(strings.Repeat("x", 10000)))
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err == nil {
+		t.Fatal("Expected an error.")
+	}
+
+	if len(err.Error()) > 512 || !strings.HasSuffix(err.Error(), "...") {
+		t.Error("Error body not truncated: ", len(err.Error()))
+	}
+}
+
+func TestFallbackErrorTruncatedInvalidUTF8(t *testing.T) {
+	// An invalid byte near the start, and a multibyte rune across the cut.
+	body := "\xff" + strings.Repeat("x", 254) + strings.Repeat("é", 100)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(502)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	_, err := ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err == nil {
+		t.Fatal("Expected an error.")
+	}
+
+	message := err.Error()
+	if !utf8.ValidString(message) {
+		t.Error("Expected valid UTF-8: ", message)
+	}
+
+	if !strings.HasSuffix(message, ": "+strings.Repeat("x", 254)+"...") {
+		t.Error("Incorrect truncated body: ", message)
+	}
+}
+
+func TestErrorRequestDetails(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Request-Id", "req_123")
+		http.Error(w, "{\"url\": \"/v1/disputes/puppy\", \"livemode\": true, \"error\": { \"status\": 404, \"message\": \"Not found\"}}", 404)
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "puppy"})
+
+	var chErr chargehound.ErrorDetails
+	if !errors.As(err, &chErr) {
+		t.Fatal("Expected API error details, got: ", err)
+	}
+
+	if chErr.URL() != "/v1/disputes/puppy" {
+		t.Error("Incorrect url: ", chErr.URL())
+	}
+
+	if !chErr.Livemode() {
+		t.Error("Incorrect livemode.")
+	}
+
+	if chErr.RequestID() != "req_123" {
+		t.Error("Incorrect request id: ", chErr.RequestID())
+	}
+
+	if chErr.Header().Get("Request-Id") != "req_123" {
+		t.Error("Missing response headers.")
+	}
+}
+
+func TestErrorRequestURLFallback(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_456")
+		w.WriteHeader(502)
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "puppy"})
+
+	var chErr chargehound.ErrorDetails
+	if !errors.As(err, &chErr) {
+		t.Fatal("Expected API error details, got: ", err)
+	}
+
+	if chErr.URL() != ts.URL+"/v1/disputes/puppy" {
+		t.Error("Incorrect url: ", chErr.URL())
+	}
+
+	if chErr.RequestID() != "req_456" {
+		t.Error("Incorrect request id: ", chErr.RequestID())
+	}
+}
+
+var classificationTests = []struct {
+	err       error
+	temporary bool
+	retryable bool
+}{
+	{nil, false, false},
+	{errors.New("boom"), false, false},
+	{context.Canceled, false, false},
+	{context.DeadlineExceeded, true, false},
+	{&chargehound.TransportError{Method: "GET", Err: syscall.ECONNRESET}, true, true},
+	{&chargehound.TransportError{Method: "POST", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true, true},
+	{&chargehound.TransportError{Method: "GET", Err: errors.New("boom")}, false, false},
+	{&chargehound.DecodeError{Method: "GET", Status: 200, Err: errors.New("boom")}, false, false},
+	{&chargehound.DecodeError{Method: "GET", Status: 200, Err: io.ErrUnexpectedEOF}, true, true},
+	{&chargehound.DecodeError{Method: "POST", Status: 200, Err: io.ErrUnexpectedEOF}, false, false},
+	{&chargehound.DecodeError{Method: "POST", Status: 201, Err: io.EOF}, false, false},
+	{&chargehound.DecodeError{Method: "POST", Status: 503, Err: io.ErrUnexpectedEOF}, true, true},
+}
+
+func TestErrorClassification(t *testing.T) {
+	for _, test := range classificationTests {
+		if chargehound.IsTemporary(test.err) != test.temporary {
+			t.Error("Incorrect temporary classification: ", test.err)
+		}
+
+		if chargehound.IsRetryable(test.err) != test.retryable {
+			t.Error("Incorrect retryable classification: ", test.err)
+		}
+	}
+}
+
+func TestAPIErrorClassification(t *testing.T) {
+	ch := chargehound.New("api_key", nil)
+
+	for _, test := range errorTests {
+		error := test.error
+		code := test.code
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, error, code)
+		}))
+		defer ts.Close()
+
+		url, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Error(err)
+		}
+
+		ch.Host = url.Host
+		ch.Protocol = url.Scheme + "://"
+
+		_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "puppy"})
+
+		temporary := code == 429 || code >= 500
+		if chargehound.IsTemporary(err) != temporary {
+			t.Error("Incorrect temporary classification: ", code)
+		}
+
+		if chargehound.IsRetryable(err) != temporary {
+			t.Error("Incorrect retryable classification: ", code)
+		}
 	}
 }
@@ -0,0 +1,92 @@
+package chargehound
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// The name of an UpdateDisputeParams field, for its ForceSendFields and
+// NullFields.
+type UpdateField string
+
+const (
+	UpdateFieldTemplate       = UpdateField("Template")
+	UpdateFieldCharge         = UpdateField("Charge")
+	UpdateFieldAccount        = UpdateField("Account")
+	UpdateFieldAccountID      = UpdateField("AccountID")
+	UpdateFieldReferenceURL   = UpdateField("ReferenceURL")
+	UpdateFieldForce          = UpdateField("Force")
+	UpdateFieldQueue          = UpdateField("Queue")
+	UpdateFieldSubmit         = UpdateField("Submit")
+	UpdateFieldFields         = UpdateField("Fields")
+	UpdateFieldProducts       = UpdateField("Products")
+	UpdateFieldCorrespondence = UpdateField("Correspondence")
+	UpdateFieldPastPayments   = UpdateField("PastPayments")
+)
+
+// Reports whether the name is a field sent in update requests.
+func (f UpdateField) IsValid() bool {
+	_, ok := updateFieldKey(f)
+	return ok
+}
+
+func (f UpdateField) String() string {
+	return string(f)
+}
+
+// The JSON name of an update body field, looked up by its Go name.
+func updateFieldKey(name UpdateField) (string, bool) {
+	field, ok := reflect.TypeOf(updateDisputeBody{}).FieldByName(string(name))
+	if !ok {
+		return "", false
+	}
+	return strings.Split(field.Tag.Get("json"), ",")[0], true
+}
+
+// Marshal the body, then add the forced fields with their values even when
+// zero, and the null fields as null.
+func marshalUpdateFields(body updateDisputeBody, forceSend, null []UpdateField) ([]byte, error) {
+	var v validator
+	v.updateFields(forceSend, null)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bodyJSON, &fields); err != nil {
+		return nil, err
+	}
+
+	bodyValue := reflect.ValueOf(body)
+	for _, name := range forceSend {
+		key, _ := updateFieldKey(name)
+		value := bodyValue.FieldByName(string(name))
+
+		// Send an empty list or object rather than null for nil values.
+		switch {
+		case value.Kind() == reflect.Slice && value.IsNil():
+			value = reflect.MakeSlice(value.Type(), 0, 0)
+		case value.Kind() == reflect.Map && value.IsNil():
+			value = reflect.MakeMap(value.Type())
+		}
+
+		raw, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = raw
+	}
+
+	for _, name := range null {
+		key, _ := updateFieldKey(name)
+		fields[key] = json.RawMessage("null")
+	}
+
+	return json.Marshal(fields)
+}
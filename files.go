@@ -0,0 +1,336 @@
+package chargehound
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// The largest file uploaded when UploadFileParams does not set MaxBytes.
+const DefaultMaxFileBytes = 8 << 20
+
+// Returned when a file's content is larger than the allowed size.
+var ErrFileTooLarge = errors.New("chargehound: file too large")
+
+// Wrapper for the Chargehound API files resource.
+type Files struct {
+	client *Client
+}
+
+// An uploaded evidence file.
+type File struct {
+	// A unique identifier for the file.
+	ID string `json:"id"`
+	// The file name.
+	Name string `json:"name"`
+	// The MIME type of the file, e.g. `application/pdf`.
+	ContentType string `json:"content_type"`
+	// The size of the file in bytes.
+	Size int64 `json:"size"`
+	// The URL of the file.
+	URL string `json:"url"`
+	// ISO 8601 timestamp - when the file was uploaded.
+	Created Timestamp `json:"created"`
+	// Set to `file`.
+	Object string `json:"object"`
+	// Is this a test or live mode file.
+	Livemode bool `json:"livemode"`
+	// The HTTP response.
+	Response HTTPResponse `json:"-"`
+}
+
+// The value to set in a dispute's Fields to attach the file as evidence.
+func (f *File) Reference() string {
+	if f.URL != "" {
+		return f.URL
+	}
+	return f.ID
+}
+
+// Params for uploading a file.
+type UploadFileParams struct {
+	// The file name, e.g. `receipt.pdf`.
+	Name string
+	// The file content. It is streamed to the API as it is read, so it is
+	// never held in memory in full.
+	Content io.Reader
+	// The MIME type of the content. Detected from the first bytes of the
+	// content if empty. (optional)
+	ContentType string
+	// The largest file allowed in bytes. Zero uses DefaultMaxFileBytes. (optional)
+	MaxBytes int64
+	// Id of the connected account for this file (if multiple accounts are connected) (optional)
+	Account string
+	// Optional http client for the request. Typically needed when using App Engine.
+	OptHTTPClient *http.Client
+}
+
+// Upload a file to use as dispute evidence. Uploads are not retried, since
+// the content can only be read once.
+func (fp *Files) Upload(params *UploadFileParams) (*File, error) {
+	return fp.UploadWithContext(context.Background(), params)
+}
+
+// Upload a file using the provided context for cancellation and deadlines.
+func (fp *Files) UploadWithContext(ctx context.Context, params *UploadFileParams) (*File, error) {
+	if params.Content == nil {
+		return nil, errors.New("chargehound: file content is required")
+	}
+
+	if params.MaxBytes < 0 {
+		return nil, errors.New("chargehound: MaxBytes must not be negative")
+	}
+
+	maxBytes := params.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxFileBytes
+	}
+
+	content := params.Content
+
+	contentType := params.ContentType
+	if contentType == "" {
+		// Sniff the type from the start of the content, then send those
+		// bytes ahead of the rest.
+		head := make([]byte, 512)
+		n, err := io.ReadFull(content, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		contentType = http.DetectContentType(head[:n])
+		content = io.MultiReader(bytes.NewReader(head[:n]), content)
+	}
+
+	content = &limitedUpload{r: content, remaining: maxBytes}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	req, err := newAPIRequestor(
+		ctx,
+		fp.client,
+		params.OptHTTPClient,
+		"POST",
+		"files",
+		nil, // streamed body
+		nil, // no query params
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.body = pr
+	req.contentType = mw.FormDataContentType()
+
+	// The client closes the pipe's reader when the request ends, which
+	// unblocks this writer if the request fails early. The error is sent
+	// before the pipe is closed so it is ready when the request fails.
+	writeErr := make(chan error, 1)
+	go func() {
+		err := writeFileMultipart(mw, params, contentType, content)
+		writeErr <- err
+		pw.CloseWithError(err)
+	}()
+
+	var v File
+	res, err := req.newRequest(&v)
+	if err != nil {
+		// Report why the upload stopped, e.g. a file over the size limit,
+		// rather than the broken request it caused.
+		select {
+		case wErr := <-writeErr:
+			if wErr != nil && !errors.Is(wErr, io.ErrClosedPipe) {
+				return nil, wErr
+			}
+		default:
+		}
+		return nil, err
+	}
+
+	v.Response = HTTPResponse{Status: res.StatusCode}
+
+	return &v, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func writeFileMultipart(mw *multipart.Writer, params *UploadFileParams, contentType string, content io.Reader) error {
+	if params.Account != "" {
+		if err := mw.WriteField("account", params.Account); err != nil {
+			return err
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(params.Name)))
+	header.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(part, content); err != nil {
+		return err
+	}
+
+	return mw.Close()
+}
+
+// A reader that fails once more than the allowed number of bytes are read.
+type limitedUpload struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (lu *limitedUpload) Read(p []byte) (int, error) {
+	if lu.remaining < 0 {
+		return 0, ErrFileTooLarge
+	}
+
+	// Read one byte past the limit to tell a file of exactly the allowed
+	// size from one that is too large. Only clamp when the limit is smaller
+	// than p, so the extra byte can't overflow.
+	if lu.remaining < int64(len(p)) {
+		p = p[:lu.remaining+1]
+	}
+
+	n, err := lu.r.Read(p)
+	lu.remaining -= int64(n)
+	if lu.remaining < 0 {
+		return 0, ErrFileTooLarge
+	}
+	return n, err
+}
+
+// A downloaded document.
+type Download struct {
+	// The URL the document was downloaded from.
+	URL string
+	// The MIME type of the document, e.g. `application/pdf`.
+	ContentType string
+	// The number of bytes written.
+	Size int64
+	// The HTTP response.
+	Response HTTPResponse
+}
+
+// Params for downloading a file.
+type DownloadFileParams struct {
+	// The URL of the file, e.g. a File's URL or a Response's ResponseURL.
+	URL string
+	// Optional http client for the request. Typically needed when using App Engine.
+	OptHTTPClient *http.Client
+}
+
+// Download a file and stream it to w. The API key is sent only when the URL
+// is on the API host, with the same scheme, host name and port as the client,
+// so it is never sent to another host, like the file storage a response URL
+// may point to. Failed requests are retried according to the client's retry
+// policy before any of the file is written; a download that fails partway is
+// not retried.
+func (fp *Files) Download(params *DownloadFileParams, w io.Writer) (*Download, error) {
+	return fp.DownloadWithContext(context.Background(), params, w)
+}
+
+// Download a file using the provided context for cancellation and deadlines.
+func (fp *Files) DownloadWithContext(ctx context.Context, params *DownloadFileParams, w io.Writer) (*Download, error) {
+	base, err := url.Parse(fp.client.Protocol + fp.client.Host + fp.client.Basepath)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := base.Parse(params.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := newAPIRequestor(
+		ctx,
+		fp.client,
+		params.OptHTTPClient,
+		"GET",
+		"",  // the url is set below
+		nil, // no body json
+		nil, // no query params
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.url = u.String()
+	req.skipAuth = !isAPIHost(u, base)
+
+	res, err := req.do()
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(res.Body)
+
+	cw := &countingWriter{w: w}
+	if _, err := io.Copy(cw, res.Body); err != nil {
+		if cw.err != nil {
+			return nil, cw.err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, &TransportError{Method: res.Request.Method, Path: res.Request.URL.Path, Err: err}
+	}
+
+	return &Download{
+		URL:         req.url,
+		ContentType: res.Header.Get("Content-Type"),
+		Size:        cw.n,
+		Response:    HTTPResponse{Status: res.StatusCode},
+	}, nil
+}
+
+// Reports whether u is on the API host, comparing host names in any case and
+// filling in the default port of the scheme.
+func isAPIHost(u, api *url.URL) bool {
+	return strings.EqualFold(u.Scheme, api.Scheme) &&
+		strings.EqualFold(u.Hostname(), api.Hostname()) &&
+		urlPort(u) == urlPort(api)
+}
+
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return "443"
+	case "http":
+		return "80"
+	}
+	return ""
+}
+
+// A writer that counts the bytes written and keeps its own errors apart from
+// errors reading the response.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	if err != nil {
+		cw.err = err
+	}
+	return n, err
+}
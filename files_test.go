@@ -0,0 +1,411 @@
+package chargehound_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A")
+
+func TestUploadFile(t *testing.T) {
+	content := append(pngHeader, bytes.Repeat([]byte{0}, 100<<10)...)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/files" {
+			t.Error("Incorrect request: ", r.Method, r.URL.Path)
+		}
+
+		if r.ContentLength != -1 {
+			t.Error("Expected a streamed body, got length: ", r.ContentLength)
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if r.FormValue("account") != "acct_1" {
+			t.Error("Incorrect account: ", r.FormValue("account"))
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if header.Filename != `receipt "1".png` {
+			t.Error("Incorrect filename: ", header.Filename)
+		}
+
+		if header.Header.Get("Content-Type") != "image/png" {
+			t.Error("Incorrect content type: ", header.Header.Get("Content-Type"))
+		}
+
+		body, _ := ioutil.ReadAll(file)
+		if !bytes.Equal(body, content) {
+			t.Error("Incorrect content, got bytes: ", len(body))
+		}
+
+		w.Write([]byte(`{"id": "file_123", "url": "https://files.chargehound.com/file_123", "size": 102408}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	file, err := ch.Files.Upload(&chargehound.UploadFileParams{
+		Name:    `receipt "1".png`,
+		Content: bytes.NewReader(content),
+		Account: "acct_1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if file.ID != "file_123" || file.Response.Status != 200 {
+		t.Error("Incorrect file: ", file.ID, file.Response.Status)
+	}
+
+	if file.Reference() != "https://files.chargehound.com/file_123" {
+		t.Error("Incorrect reference: ", file.Reference())
+	}
+}
+
+func TestUploadFileContentType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if header.Header.Get("Content-Type") != "application/pdf" {
+			t.Error("Incorrect content type: ", header.Header.Get("Content-Type"))
+		}
+
+		w.Write([]byte(`{"id": "file_123"}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	_, err := ch.Files.Upload(&chargehound.UploadFileParams{
+		Name:        "receipt",
+		Content:     strings.NewReader("not sniffed"),
+		ContentType: "application/pdf",
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUploadFileTooLarge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+		w.Write([]byte(`{"id": "file_123"}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	for _, size := range []int{100, 10 << 10} {
+		_, err := ch.Files.Upload(&chargehound.UploadFileParams{
+			Name:     "large.bin",
+			Content:  bytes.NewReader(make([]byte, size)),
+			MaxBytes: 99,
+		})
+		if !errors.Is(err, chargehound.ErrFileTooLarge) {
+			t.Error("Expected ErrFileTooLarge, got: ", err)
+		}
+	}
+
+	_, err := ch.Files.Upload(&chargehound.UploadFileParams{
+		Name:     "exact.bin",
+		Content:  bytes.NewReader(make([]byte, 99)),
+		MaxBytes: 99,
+	})
+	if err != nil {
+		t.Error("Expected a file of exactly MaxBytes to upload, got: ", err)
+	}
+}
+
+func TestUploadFileMaxBytes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+		w.Write([]byte(`{"id": "file_123"}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	// The largest limit must not overflow when reading one byte past it.
+	_, err := ch.Files.Upload(&chargehound.UploadFileParams{
+		Name:     "receipt.txt",
+		Content:  strings.NewReader("receipt"),
+		MaxBytes: math.MaxInt64,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = ch.Files.Upload(&chargehound.UploadFileParams{
+		Name:     "receipt.txt",
+		Content:  strings.NewReader("receipt"),
+		MaxBytes: -1,
+	})
+	if err == nil {
+		t.Error("Expected an error for a negative MaxBytes.")
+	}
+}
+
+func TestUploadFileNotRetried(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		io.Copy(ioutil.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Files.Upload(&chargehound.UploadFileParams{
+		Name:    "receipt.txt",
+		Content: strings.NewReader("receipt"),
+	})
+	if !errors.Is(err, chargehound.ErrServiceUnavailable) {
+		t.Error("Expected ErrServiceUnavailable, got: ", err)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Error("Expected one request, got: ", n)
+	}
+}
+
+func newResponseServer(t *testing.T, documentURL func() string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/disputes/dp_123/response" {
+			t.Error("Incorrect path: ", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(chargehound.Response{DisputeID: "dp_123", ResponseURL: documentURL()})
+	}))
+}
+
+func TestDownloadResponse(t *testing.T) {
+	var api *httptest.Server
+	api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/disputes/dp_123/response":
+			json.NewEncoder(w).Encode(chargehound.Response{ResponseURL: api.URL + "/v1/disputes/dp_123/response.pdf"})
+		case "/v1/disputes/dp_123/response.pdf":
+			if user, _, ok := r.BasicAuth(); !ok || user != "api_key" {
+				t.Error("Expected the API key on the API host.")
+			}
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.4 document"))
+		default:
+			t.Error("Incorrect path: ", r.URL.Path)
+		}
+	}))
+	defer api.Close()
+
+	ch := newTestClient(t, api)
+
+	var buf bytes.Buffer
+	download, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "%PDF-1.4 document" {
+		t.Error("Incorrect document: ", buf.String())
+	}
+
+	if download.ContentType != "application/pdf" || download.Size != int64(buf.Len()) || download.Response.Status != 200 {
+		t.Error("Incorrect download: ", download)
+	}
+}
+
+func TestDownloadResponseOtherHost(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Expected no API key on another host.")
+		}
+		w.Write([]byte("document"))
+	}))
+	defer storage.Close()
+
+	api := newResponseServer(t, func() string { return storage.URL + "/dp_123.pdf" })
+	defer api.Close()
+
+	ch := newTestClient(t, api)
+
+	var buf bytes.Buffer
+	if _, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, &buf); err != nil {
+		t.Error(err)
+	}
+
+	if buf.String() != "document" {
+		t.Error("Incorrect document: ", buf.String())
+	}
+}
+
+func TestDownloadResponseRetries(t *testing.T) {
+	var requests int32
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("document"))
+	}))
+	defer storage.Close()
+
+	api := newResponseServer(t, func() string { return storage.URL + "/dp_123.pdf" })
+	defer api.Close()
+
+	ch := newRetryClient(t, api)
+
+	var buf bytes.Buffer
+	if _, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, &buf); err != nil {
+		t.Error(err)
+	}
+
+	if buf.String() != "document" || atomic.LoadInt32(&requests) != 2 {
+		t.Error("Incorrect retried download: ", buf.String(), requests)
+	}
+}
+
+func TestDownloadResponseRetryAttempts(t *testing.T) {
+	var requests int32
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer storage.Close()
+
+	api := newResponseServer(t, func() string { return storage.URL + "/dp_123.pdf" })
+	defer api.Close()
+
+	ch := newRetryClient(t, api)
+
+	_, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, ioutil.Discard)
+	if !errors.Is(err, chargehound.ErrServiceUnavailable) {
+		t.Error("Expected ErrServiceUnavailable, got: ", err)
+	}
+
+	// Only the retry policy retries, so a failing download makes at most
+	// MaxAttempts requests.
+	if n := atomic.LoadInt32(&requests); n != int32(ch.RetryPolicy.MaxAttempts) {
+		t.Error("Expected ", ch.RetryPolicy.MaxAttempts, " requests, got: ", n)
+	}
+}
+
+func TestDownloadResponseBodyFailureNotRetried(t *testing.T) {
+	var requests int32
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Promise a body but cut the connection before sending any of it.
+		w.Header().Set("Content-Length", "100")
+	}))
+	defer storage.Close()
+
+	api := newResponseServer(t, func() string { return storage.URL + "/dp_123.pdf" })
+	defer api.Close()
+
+	ch := newRetryClient(t, api)
+
+	_, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, ioutil.Discard)
+
+	var transportErr *chargehound.TransportError
+	if !errors.As(err, &transportErr) {
+		t.Error("Expected a TransportError, got: ", err)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Error("Expected one request, got: ", n)
+	}
+}
+
+func TestDownloadAPIHostAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, ok := r.BasicAuth(); !ok || user != "api_key" {
+			t.Error("Expected the API key on the API host.")
+		}
+		w.Write([]byte("document"))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	// The same host written differently is still the API host.
+	port := ts.Listener.Addr().(*net.TCPAddr).Port
+	ch.Host = fmt.Sprintf("localhost:%d", port)
+
+	var buf bytes.Buffer
+	_, err := ch.Files.Download(&chargehound.DownloadFileParams{
+		URL: fmt.Sprintf("http://LocalHost:%d/v1/files/file_123", port),
+	}, &buf)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if buf.String() != "document" {
+		t.Error("Incorrect document: ", buf.String())
+	}
+}
+
+func TestDownloadResponseNotRetriedAfterWrite(t *testing.T) {
+	var requests int32
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Promise more than is sent, so the connection is cut short.
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("partial"))
+	}))
+	defer storage.Close()
+
+	api := newResponseServer(t, func() string { return storage.URL + "/dp_123.pdf" })
+	defer api.Close()
+
+	ch := newRetryClient(t, api)
+
+	var buf bytes.Buffer
+	_, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, &buf)
+
+	var transportErr *chargehound.TransportError
+	if !errors.As(err, &transportErr) {
+		t.Error("Expected a TransportError, got: ", err)
+	}
+
+	if buf.String() != "partial" || atomic.LoadInt32(&requests) != 1 {
+		t.Error("Incorrect partial download: ", buf.String(), requests)
+	}
+}
+
+func TestDownloadResponseMissing(t *testing.T) {
+	api := newResponseServer(t, func() string { return "" })
+	defer api.Close()
+
+	ch := newTestClient(t, api)
+
+	if _, err := ch.Disputes.DownloadResponse(&chargehound.RetrieveDisputeParams{ID: "dp_123"}, ioutil.Discard); err == nil {
+		t.Error("Expected an error for a missing document.")
+	}
+}
@@ -0,0 +1,121 @@
+package chargehound
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// A time range for filtering a dispute list. Either bound may be left zero
+// for an open range.
+type DateRange struct {
+	// Only include disputes at or after this time. (optional)
+	From time.Time
+	// Only include disputes before this time. (optional)
+	To time.Time
+}
+
+// Adds the range bounds to the query as `name[gte]` and `name[lt]`.
+func (dr *DateRange) encode(q url.Values, name string) {
+	if dr == nil {
+		return
+	}
+
+	if !dr.From.IsZero() {
+		q.Set(name+"[gte]", dr.From.UTC().Format(time.RFC3339))
+	}
+
+	if !dr.To.IsZero() {
+		q.Set(name+"[lt]", dr.To.UTC().Format(time.RFC3339))
+	}
+}
+
+// Reports whether the timestamp falls within the range. Missing timestamps
+// never match a range.
+func (dr *DateRange) contains(ts Timestamp) bool {
+	if dr == nil {
+		return true
+	}
+
+	t := ts.Time
+	if t.IsZero() {
+		return false
+	}
+
+	if !dr.From.IsZero() && t.Before(dr.From) {
+		return false
+	}
+
+	if !dr.To.IsZero() && !t.Before(dr.To) {
+		return false
+	}
+
+	return true
+}
+
+// Reports whether the dispute matches the filters that the API does not
+// apply itself. ListAll uses this to filter each page, so filters behave the
+// same whether or not the API supports them.
+func (params *ListDisputesParams) matches(d *Dispute) bool {
+	return params.Created.contains(d.Created) &&
+		params.DisputedAt.contains(d.DisputedAt) &&
+		params.DueBy.contains(d.DueBy) &&
+		params.matchesReason(d.Reason) &&
+		params.matchesKind(d.Kind) &&
+		params.matchesProcessor(d.Processor) &&
+		params.matchesState(d.State) &&
+		matchesAny(params.Account, d.Account) &&
+		matchesAny(params.Currency, d.Currency)
+}
+
+func (params *ListDisputesParams) matchesState(state DisputeState) bool {
+	for _, s := range params.State {
+		if s == state {
+			return true
+		}
+	}
+	return len(params.State) == 0
+}
+
+func (params *ListDisputesParams) matchesReason(reason DisputeReason) bool {
+	for _, r := range params.Reason {
+		if r == reason {
+			return true
+		}
+	}
+	return len(params.Reason) == 0
+}
+
+func (params *ListDisputesParams) matchesKind(kind DisputeKind) bool {
+	for _, k := range params.Kind {
+		if k == kind {
+			return true
+		}
+	}
+	return len(params.Kind) == 0
+}
+
+func (params *ListDisputesParams) matchesProcessor(processor Processor) bool {
+	for _, p := range params.Processor {
+		if p == processor {
+			return true
+		}
+	}
+	return len(params.Processor) == 0
+}
+
+// Reports whether the value is one of the allowed values, or if no values are
+// given. Comparison is case insensitive to match currency codes in any case.
+func matchesAny(allowed []string, value string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, a := range allowed {
+		if strings.EqualFold(a, value) {
+			return true
+		}
+	}
+
+	return false
+}
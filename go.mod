@@ -1,3 +1,3 @@
-module github.com/chargehound/chargehound-go/v8.6.2
+module github.com/chargehound/chargehound-go/v9
 
 go 1.17
\ No newline at end of file
@@ -0,0 +1,210 @@
+package chargehound
+
+import "context"
+
+// An iterator over every dispute matching a list request, fetching pages
+// lazily as the iteration advances. Create one with Disputes.ListAll.
+//
+//	it := ch.Disputes.ListAll(&chargehound.ListDisputesParams{})
+//	defer it.Close()
+//	for it.Next() {
+//		dispute := it.Dispute()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type DisputeIter struct {
+	pager   *disputePager
+	pages   chan disputePage
+	cancel  context.CancelFunc
+	page    []Dispute
+	current Dispute
+	count   int
+	done    bool
+	err     error
+}
+
+// Iterate over every dispute matching the list params. Iteration starts after
+// StartingAfter, or walks backwards from EndingBefore if it is set instead,
+// and stops after MaxResults disputes if it is set. Filters the API does not
+// support are applied to each page. Set Prefetch to fetch pages in the
+// background while the caller processes the current one.
+func (dp *Disputes) ListAll(params *ListDisputesParams) *DisputeIter {
+	return dp.ListAllWithContext(context.Background(), params)
+}
+
+// Iterate over every dispute matching the list params using the provided
+// context for every page request.
+func (dp *Disputes) ListAllWithContext(ctx context.Context, params *ListDisputesParams) *DisputeIter {
+	ctx, cancel := context.WithCancel(ctx)
+
+	it := &DisputeIter{
+		pager: &disputePager{
+			ctx:      ctx,
+			disputes: dp,
+			params:   *params,
+			backward: params.StartingAfter == "" && params.EndingBefore != "",
+			hasMore:  true,
+		},
+		cancel: cancel,
+	}
+
+	if params.Prefetch > 0 {
+		it.pages = make(chan disputePage, params.Prefetch)
+		go it.pager.prefetch(it.pages)
+	}
+
+	return it
+}
+
+// Advance to the next dispute, fetching the next page if needed. Returns
+// false when there are no more disputes or a request failed, see Err.
+func (it *DisputeIter) Next() bool {
+	if it.err != nil || (it.pager.params.MaxResults > 0 && it.count >= it.pager.params.MaxResults) {
+		it.Close()
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.done {
+			it.Close()
+			return false
+		}
+
+		it.page, it.err = it.nextPage()
+		if it.err != nil {
+			it.Close()
+			return false
+		}
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	it.count++
+
+	return true
+}
+
+// The current dispute.
+func (it *DisputeIter) Dispute() Dispute {
+	return it.current
+}
+
+// The error that stopped the iteration, if any.
+func (it *DisputeIter) Err() error {
+	return it.err
+}
+
+// Stop the iteration and release its resources, including any background
+// page requests. Only needed when stopping before Next returns false, and
+// safe to call more than once.
+func (it *DisputeIter) Close() {
+	it.cancel()
+}
+
+func (it *DisputeIter) nextPage() ([]Dispute, error) {
+	if it.pages != nil {
+		page, ok := <-it.pages
+		if !ok {
+			it.done = true
+			return nil, it.pager.err
+		}
+		return page.data, page.err
+	}
+
+	if !it.pager.hasMore {
+		it.done = true
+		return nil, nil
+	}
+
+	return it.pager.next()
+}
+
+// A page of disputes, or the error that ended the listing.
+type disputePage struct {
+	data []Dispute
+	err  error
+}
+
+// Fetches consecutive pages of a list request.
+type disputePager struct {
+	ctx      context.Context
+	disputes *Disputes
+	params   ListDisputesParams
+	backward bool
+	hasMore  bool
+	fetched  int
+	// Why prefetching stopped before the listing ended, set before the pages
+	// channel is closed.
+	err error
+}
+
+// Fetch the next page and move the cursor past it.
+func (p *disputePager) next() ([]Dispute, error) {
+	list, err := p.disputes.ListWithContext(p.ctx, &p.params)
+	if err != nil {
+		p.hasMore = false
+		return nil, err
+	}
+
+	page := list.Data
+	p.hasMore = list.HasMore && len(page) > 0
+
+	if len(page) == 0 {
+		return page, nil
+	}
+
+	if p.backward {
+		// Pages keep the API's order, so walking backwards means reading each
+		// page in reverse and continuing before its first dispute.
+		for i, j := 0, len(page)-1; i < j; i, j = i+1, j-1 {
+			page[i], page[j] = page[j], page[i]
+		}
+		p.params.EndingBefore = page[len(page)-1].ID
+	} else {
+		p.params.StartingAfter = page[len(page)-1].ID
+	}
+
+	// Apply the filters the API may not support. The cursor above still
+	// moves past the disputes filtered out.
+	matched := page[:0]
+	for i := range page {
+		if p.params.matches(&page[i]) {
+			matched = append(matched, page[i])
+		}
+	}
+
+	p.fetched += len(matched)
+	if p.params.MaxResults > 0 && p.fetched >= p.params.MaxResults {
+		p.hasMore = false
+	}
+
+	return matched, nil
+}
+
+// Fetch pages ahead of the caller until the listing ends, a request fails or
+// the iteration is closed. The channel's capacity bounds how many pages are
+// held in memory.
+func (p *disputePager) prefetch(pages chan<- disputePage) {
+	defer close(pages)
+
+	for p.hasMore {
+		data, err := p.next()
+
+		select {
+		case pages <- disputePage{data: data, err: err}:
+		case <-p.ctx.Done():
+			// The page can't be delivered, so record why the listing stopped
+			// short rather than end it as if it were complete.
+			if err == nil {
+				err = p.ctx.Err()
+			}
+			p.err = err
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
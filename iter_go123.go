@@ -0,0 +1,41 @@
+//go:build go1.23
+// +build go1.23
+
+package chargehound
+
+import (
+	"context"
+	"iter"
+)
+
+// Iterate over every dispute matching the list params with a range-over-func
+// loop. Iteration stops at the first error, which is yielded with a zero
+// dispute. See ListAll.
+//
+//	for dispute, err := range ch.Disputes.Iter(&chargehound.ListDisputesParams{}) {
+//		if err != nil {
+//			...
+//		}
+//	}
+func (dp *Disputes) Iter(params *ListDisputesParams) iter.Seq2[Dispute, error] {
+	return dp.IterWithContext(context.Background(), params)
+}
+
+// Iterate over every dispute matching the list params with a range-over-func
+// loop using the provided context for every page request.
+func (dp *Disputes) IterWithContext(ctx context.Context, params *ListDisputesParams) iter.Seq2[Dispute, error] {
+	return func(yield func(Dispute, error) bool) {
+		it := dp.ListAllWithContext(ctx, params)
+		defer it.Close()
+
+		for it.Next() {
+			if !yield(it.Dispute(), nil) {
+				return
+			}
+		}
+
+		if err := it.Err(); err != nil {
+			yield(Dispute{}, err)
+		}
+	}
+}
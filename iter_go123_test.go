@@ -0,0 +1,34 @@
+//go:build go1.23
+// +build go1.23
+
+package chargehound_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func TestIter(t *testing.T) {
+	ts := newPagingServer(t, 7, nil)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	var ids []string
+	for dispute, err := range ch.Disputes.Iter(&chargehound.ListDisputesParams{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, dispute.ID)
+		if len(ids) == 5 {
+			break
+		}
+	}
+
+	if fmt.Sprint(ids) != "[dp_1 dp_2 dp_3 dp_4 dp_5]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+}
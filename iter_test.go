@@ -0,0 +1,407 @@
+package chargehound_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+// Serves the disputes dp_1 to dp_n in pages, honoring the limit,
+// starting_after and ending_before query params.
+func newPagingServer(t *testing.T, n int, requests *int32) *httptest.Server {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("dp_%d", i+1)
+	}
+
+	indexOf := func(id string) int {
+		for i, v := range ids {
+			if v == id {
+				return i
+			}
+		}
+		t.Error("Unknown cursor: ", id)
+		return 0
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			atomic.AddInt32(requests, 1)
+		}
+
+		if r.URL.Path != "/v1/disputes" {
+			t.Error("Incorrect path.")
+		}
+
+		q := r.URL.Query()
+		limit := 3
+		if q.Get("limit") != "" {
+			limit, _ = strconv.Atoi(q.Get("limit"))
+		}
+
+		start, end := 0, len(ids)
+		if q.Get("starting_after") != "" {
+			start = indexOf(q.Get("starting_after")) + 1
+			if start+limit < end {
+				end = start + limit
+			}
+		} else if q.Get("ending_before") != "" {
+			end = indexOf(q.Get("ending_before"))
+			if end-limit > 0 {
+				start = end - limit
+			}
+		} else if limit < end {
+			end = limit
+		}
+
+		var data []chargehound.Dispute
+		for _, id := range ids[start:end] {
+			data = append(data, chargehound.Dispute{ID: id})
+		}
+
+		hasMore := end < len(ids)
+		if q.Get("ending_before") != "" {
+			hasMore = start > 0
+		}
+
+		json.NewEncoder(w).Encode(chargehound.DisputeList{Data: data, HasMore: hasMore})
+	}))
+}
+
+func newTestClient(t *testing.T, ts *httptest.Server) *chargehound.Client {
+	ch := chargehound.New("api_key", nil)
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	return ch
+}
+
+func collectIDs(t *testing.T, it *chargehound.DisputeIter) []string {
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Dispute().ID)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Error(err)
+	}
+
+	return ids
+}
+
+func TestListAll(t *testing.T) {
+	var requests int32
+	ts := newPagingServer(t, 7, &requests)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{}))
+
+	if fmt.Sprint(ids) != "[dp_1 dp_2 dp_3 dp_4 dp_5 dp_6 dp_7]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+
+	if requests != 3 {
+		t.Error("Expected 3 page requests, got: ", requests)
+	}
+}
+
+func TestListAllStartingAfter(t *testing.T) {
+	ts := newPagingServer(t, 7, nil)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{StartingAfter: "dp_2", Limit: 2}))
+
+	if fmt.Sprint(ids) != "[dp_3 dp_4 dp_5 dp_6 dp_7]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+}
+
+func TestListAllEndingBefore(t *testing.T) {
+	ts := newPagingServer(t, 7, nil)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{EndingBefore: "dp_6"}))
+
+	if fmt.Sprint(ids) != "[dp_5 dp_4 dp_3 dp_2 dp_1]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+}
+
+func TestListAllMaxResults(t *testing.T) {
+	var requests int32
+	ts := newPagingServer(t, 7, &requests)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{MaxResults: 4}))
+
+	if fmt.Sprint(ids) != "[dp_1 dp_2 dp_3 dp_4]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+
+	if requests != 2 {
+		t.Error("Expected 2 page requests, got: ", requests)
+	}
+}
+
+func TestListAllError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("starting_after") == "" {
+			json.NewEncoder(w).Encode(chargehound.DisputeList{Data: []chargehound.Dispute{{ID: "dp_1"}}, HasMore: true})
+			return
+		}
+
+		http.Error(w, "{\"error\": { \"status\": 500, \"message\": \"Server error\"}}", 500)
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	it := ch.Disputes.ListAll(&chargehound.ListDisputesParams{})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Dispute().ID)
+	}
+
+	if fmt.Sprint(ids) != "[dp_1]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+
+	if it.Err() == nil {
+		t.Error("Expected an error.")
+	}
+
+	if it.Next() {
+		t.Error("Iteration continued after an error.")
+	}
+}
+
+func TestListAllPrefetch(t *testing.T) {
+	var requests int32
+	ts := newPagingServer(t, 100, &requests)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	it := ch.Disputes.ListAll(&chargehound.ListDisputesParams{Prefetch: 2})
+	defer it.Close()
+
+	if !it.Next() || it.Dispute().ID != "dp_1" {
+		t.Fatal("Incorrect first dispute.")
+	}
+
+	// While the first page is processed, the next pages are fetched up to the
+	// buffer depth: two buffered pages and one waiting to be buffered.
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&requests) < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&requests); n != 4 {
+		t.Error("Expected 4 page requests, got: ", n)
+	}
+
+	ids := []string{it.Dispute().ID}
+	for it.Next() {
+		ids = append(ids, it.Dispute().ID)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Error(err)
+	}
+
+	if len(ids) != 100 {
+		t.Fatal("Expected 100 disputes, got: ", len(ids))
+	}
+
+	for i, id := range ids {
+		if id != fmt.Sprintf("dp_%d", i+1) {
+			t.Fatal("Incorrect order at: ", i, id)
+		}
+	}
+}
+
+func TestListAllPrefetchMaxResults(t *testing.T) {
+	var requests int32
+	ts := newPagingServer(t, 100, &requests)
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{Prefetch: 5, MaxResults: 7}))
+
+	if fmt.Sprint(ids) != "[dp_1 dp_2 dp_3 dp_4 dp_5 dp_6 dp_7]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Error("Expected 3 page requests, got: ", n)
+	}
+}
+
+func TestListAllPrefetchError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("starting_after") == "" {
+			json.NewEncoder(w).Encode(chargehound.DisputeList{Data: []chargehound.Dispute{{ID: "dp_1"}}, HasMore: true})
+			return
+		}
+
+		http.Error(w, "{\"error\": { \"status\": 500, \"message\": \"Server error\"}}", 500)
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	it := ch.Disputes.ListAll(&chargehound.ListDisputesParams{Prefetch: 3})
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Dispute().ID)
+	}
+
+	if fmt.Sprint(ids) != "[dp_1]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+
+	if it.Err() == nil {
+		t.Error("Expected an error.")
+	}
+}
+
+func TestListAllAccountAndStateFilters(t *testing.T) {
+	disputes := []chargehound.Dispute{
+		{ID: "dp_1", Account: "acct_1", State: chargehound.DisputeStateNeedsResponse},
+		{ID: "dp_2", Account: "acct_2", State: chargehound.DisputeStateNeedsResponse},
+		{ID: "dp_3", Account: "acct_1", State: chargehound.DisputeStateWon},
+		{ID: "dp_4", Account: "acct_1", State: chargehound.DisputeStateNeedsResponse},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("account") != "acct_1" || q.Get("state") != "needs_response" {
+			t.Error("Incorrect query: ", r.URL.RawQuery)
+		}
+
+		// The server ignores the filters.
+		json.NewEncoder(w).Encode(chargehound.DisputeList{Data: disputes})
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{
+		Account: []string{"acct_1"},
+		State:   []chargehound.DisputeState{chargehound.DisputeStateNeedsResponse},
+	}))
+
+	if fmt.Sprint(ids) != "[dp_1 dp_4]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+}
+
+func TestListAllPrefetchCanceled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("starting_after") == "" {
+			json.NewEncoder(w).Encode(chargehound.DisputeList{Data: []chargehound.Dispute{{ID: "dp_1"}}, HasMore: true})
+			return
+		}
+
+		// Hold later pages until the client gives up.
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	// Whether the failed page or the cancellation wins inside the prefetcher
+	// is random, so try a few times.
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		it := ch.Disputes.ListAllWithContext(ctx, &chargehound.ListDisputesParams{Prefetch: 1})
+
+		if !it.Next() || it.Dispute().ID != "dp_1" {
+			t.Fatal("Expected the first dispute, got: ", it.Err())
+		}
+
+		cancel()
+
+		if it.Next() {
+			t.Error("Unexpected dispute: ", it.Dispute().ID)
+		}
+
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Fatal("Expected context.Canceled, got: ", it.Err())
+		}
+	}
+}
+
+func TestListAllClientSideFilters(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	disputes := []chargehound.Dispute{
+		{ID: "dp_1", Reason: "fraudulent", DueBy: chargehound.Timestamp{Time: now.Add(24 * time.Hour)}},
+		{ID: "dp_2", Reason: "general", DueBy: chargehound.Timestamp{Time: now.Add(24 * time.Hour)}},
+		{ID: "dp_3", Reason: "fraudulent", DueBy: chargehound.Timestamp{Time: now.Add(5 * 24 * time.Hour)}},
+		{ID: "dp_4", Reason: "fraudulent"},
+		{ID: "dp_5", Reason: "fraudulent", DueBy: chargehound.Timestamp{Time: now.Add(48 * time.Hour)}},
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("reason") != "fraudulent" {
+			t.Error("Incorrect reason query.")
+		}
+
+		if q.Get("due_by[gte]") != "2024-05-01T12:00:00Z" || q.Get("due_by[lt]") != "2024-05-04T12:00:00Z" {
+			t.Error("Incorrect due by query: ", r.URL.RawQuery)
+		}
+
+		// The server ignores the filters and pages through every dispute.
+		if q.Get("starting_after") == "" {
+			json.NewEncoder(w).Encode(chargehound.DisputeList{Data: disputes[:3], HasMore: true})
+		} else {
+			json.NewEncoder(w).Encode(chargehound.DisputeList{Data: disputes[3:]})
+		}
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	ids := collectIDs(t, ch.Disputes.ListAll(&chargehound.ListDisputesParams{
+		Reason: []chargehound.DisputeReason{chargehound.DisputeReasonFraudulent},
+		DueBy:  &chargehound.DateRange{From: now, To: now.Add(72 * time.Hour)},
+	}))
+
+	if fmt.Sprint(ids) != "[dp_1 dp_5]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+}
@@ -0,0 +1,165 @@
+package chargehound
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// An amount of money in the minor unit of its currency, e.g. cents for USD or
+// yen for JPY. Encodes as `{"amount": 1000, "currency": "usd"}`, the same
+// attribute names the API uses.
+type Money struct {
+	// The amount in the minor currency unit.
+	Amount int64 `json:"amount"`
+	// The ISO 4217 currency code, e.g. 'USD'.
+	Currency string `json:"currency"`
+}
+
+// Returned when adding or subtracting amounts in different currencies.
+var ErrCurrencyMismatch = errors.New("chargehound: currency mismatch")
+
+// Returned when the result of an arithmetic operation or conversion does not
+// fit in an int64.
+var ErrAmountOverflow = errors.New("chargehound: amount overflow")
+
+// ISO 4217 currencies whose minor unit is not a hundredth of the major unit.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// The number of decimal digits of the minor unit of an ISO 4217 currency,
+// e.g. 2 for USD, 0 for JPY and 3 for KWD. Currency codes are case
+// insensitive, and unknown currencies have 2 digits.
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Create an amount of money from a value in the major currency unit, e.g.
+// 12.34 USD. The value is rounded to the nearest minor unit. Returns
+// ErrAmountOverflow if the value is NaN, infinite or too large for an int64
+// amount.
+func MoneyFromMajor(value float64, currency string) (Money, error) {
+	amount := math.Round(value * math.Pow10(CurrencyExponent(currency)))
+
+	// -2^63 and 2^63 are exact as float64, so the comparison is too.
+	if math.IsNaN(amount) || amount < math.MinInt64 || amount >= -math.MinInt64 {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return Money{Amount: int64(amount), Currency: currency}, nil
+}
+
+// The amount in the major currency unit, e.g. 12.34 for 1234 cents.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(CurrencyExponent(m.Currency))
+}
+
+// Format the amount in the major currency unit followed by the upper case
+// currency code, e.g. "12.34 USD" or "1234 JPY".
+func (m Money) String() string {
+	exp := CurrencyExponent(m.Currency)
+	currency := strings.ToUpper(m.Currency)
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+
+	// Format the integer digits rather than a float so the result is exact
+	// for every amount.
+	digits := strings.TrimPrefix(strconv.FormatInt(amount, 10), "-")
+
+	if exp == 0 {
+		return sign + digits + " " + currency
+	}
+
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+
+	split := len(digits) - exp
+	return sign + digits[:split] + "." + digits[split:] + " " + currency
+}
+
+// Reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add two amounts in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if !strings.EqualFold(m.Currency, other.Currency) {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Subtract an amount in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Multiply the amount by an integer factor, e.g. a product quantity.
+func (m Money) Mul(factor int64) (Money, error) {
+	if m.Amount == 0 || factor == 0 {
+		return Money{Currency: m.Currency}, nil
+	}
+
+	product := m.Amount * factor
+	if product/factor != m.Amount || (factor == -1 && m.Amount == math.MinInt64) {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// The amount of the disputed charge.
+func (d *Dispute) AmountMoney() Money {
+	return Money{Amount: int64(d.Amount), Currency: d.Currency}
+}
+
+// The dispute fee. The fee is charged in the currency of the deduction if
+// there is one, otherwise in the currency of the disputed charge.
+func (d *Dispute) FeeMoney() Money {
+	currency := d.ReversalCurrency
+	if currency == "" {
+		currency = d.Currency
+	}
+	return Money{Amount: int64(d.Fee), Currency: currency}
+}
+
+// The amount deducted due to the chargeback.
+func (d *Dispute) ReversalMoney() Money {
+	return Money{Amount: int64(d.ReversalAmount), Currency: d.ReversalCurrency}
+}
+
+// The amount of the past payment.
+func (p *PastPayment) Money() Money {
+	return Money{Amount: int64(p.Amount), Currency: p.Currency}
+}
+
+// The amount of the product in the given currency. Products don't have a
+// currency of their own, they use the currency of the dispute.
+func (p *Product) Money(currency string) Money {
+	return Money{Amount: int64(p.Amount), Currency: currency}
+}
@@ -0,0 +1,139 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+var moneyStringTests = []struct {
+	money    chargehound.Money
+	expected string
+}{
+	{chargehound.Money{Amount: 1234, Currency: "usd"}, "12.34 USD"},
+	{chargehound.Money{Amount: 5, Currency: "USD"}, "0.05 USD"},
+	{chargehound.Money{Amount: -1234, Currency: "eur"}, "-12.34 EUR"},
+	{chargehound.Money{Amount: 1234, Currency: "jpy"}, "1234 JPY"},
+	{chargehound.Money{Amount: 1234, Currency: "KWD"}, "1.234 KWD"},
+	{chargehound.Money{Amount: math.MinInt64, Currency: "usd"}, "-92233720368547758.08 USD"},
+}
+
+func TestMoneyString(t *testing.T) {
+	for _, test := range moneyStringTests {
+		if test.money.String() != test.expected {
+			t.Error("Expected ", test.expected, ", got: ", test.money.String())
+		}
+	}
+}
+
+func TestMoneyMajor(t *testing.T) {
+	if m, err := chargehound.MoneyFromMajor(12.34, "usd"); err != nil || m.Amount != 1234 {
+		t.Error("Incorrect USD amount: ", m.Amount, err)
+	}
+
+	if m, err := chargehound.MoneyFromMajor(1234, "JPY"); err != nil || m.Amount != 1234 || m.Major() != 1234 {
+		t.Error("Incorrect JPY amount: ", m.Amount, err)
+	}
+
+	if m := (chargehound.Money{Amount: 1500, Currency: "bhd"}); m.Major() != 1.5 {
+		t.Error("Incorrect BHD amount: ", m.Major())
+	}
+}
+
+func TestMoneyFromMajorOverflow(t *testing.T) {
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e17, -1e17, math.MaxFloat64} {
+		if _, err := chargehound.MoneyFromMajor(value, "usd"); !errors.Is(err, chargehound.ErrAmountOverflow) {
+			t.Error("Expected an overflow error for ", value, ", got: ", err)
+		}
+	}
+
+	if m, err := chargehound.MoneyFromMajor(-9e16, "usd"); err != nil || m.Amount != -9e18 {
+		t.Error("Incorrect large amount: ", m.Amount, err)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := chargehound.Money{Amount: 1000, Currency: "usd"}
+
+	sum, err := a.Add(chargehound.Money{Amount: 1500, Currency: "USD"})
+	if err != nil || sum.Amount != 2500 {
+		t.Error("Incorrect sum: ", sum, err)
+	}
+
+	diff, err := a.Sub(chargehound.Money{Amount: 1500, Currency: "usd"})
+	if err != nil || diff.Amount != -500 {
+		t.Error("Incorrect difference: ", diff, err)
+	}
+
+	product, err := a.Mul(3)
+	if err != nil || product.Amount != 3000 {
+		t.Error("Incorrect product: ", product, err)
+	}
+
+	if _, err := a.Add(chargehound.Money{Amount: 1, Currency: "jpy"}); err != chargehound.ErrCurrencyMismatch {
+		t.Error("Expected a currency mismatch, got: ", err)
+	}
+
+	max := chargehound.Money{Amount: math.MaxInt64, Currency: "usd"}
+	if _, err := max.Add(chargehound.Money{Amount: 1, Currency: "usd"}); err != chargehound.ErrAmountOverflow {
+		t.Error("Expected an overflow, got: ", err)
+	}
+
+	if _, err := a.Sub(chargehound.Money{Amount: math.MinInt64, Currency: "usd"}); err != chargehound.ErrAmountOverflow {
+		t.Error("Expected an overflow, got: ", err)
+	}
+
+	if _, err := max.Mul(2); err != chargehound.ErrAmountOverflow {
+		t.Error("Expected an overflow, got: ", err)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	b, err := json.Marshal(chargehound.Money{Amount: 1000, Currency: "usd"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != `{"amount":1000,"currency":"usd"}` {
+		t.Error("Incorrect json: ", string(b))
+	}
+
+	var m chargehound.Money
+	if err := json.Unmarshal(b, &m); err != nil || m.Amount != 1000 || m.Currency != "usd" {
+		t.Error("Incorrect money: ", m, err)
+	}
+}
+
+func TestDisputeMoney(t *testing.T) {
+	var dispute chargehound.Dispute
+	err := json.Unmarshal([]byte(`{
+		"amount": 500,
+		"currency": "jpy",
+		"fee": 1500,
+		"reversal_amount": 450,
+		"reversal_currency": "usd",
+		"past_payments": [{"amount": 300, "currency": "jpy"}]
+	}`), &dispute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dispute.AmountMoney().String() != "500 JPY" {
+		t.Error("Incorrect amount: ", dispute.AmountMoney())
+	}
+
+	if dispute.FeeMoney().String() != "15.00 USD" {
+		t.Error("Incorrect fee: ", dispute.FeeMoney())
+	}
+
+	if dispute.ReversalMoney().String() != "4.50 USD" {
+		t.Error("Incorrect reversal: ", dispute.ReversalMoney())
+	}
+
+	if dispute.PastPayments[0].Money().String() != "300 JPY" {
+		t.Error("Incorrect past payment: ", dispute.PastPayments[0].Money())
+	}
+}
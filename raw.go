@@ -0,0 +1,181 @@
+package chargehound
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+func (d *Dispute) UnmarshalJSON(data []byte) error {
+	// A defined type without the UnmarshalJSON method, to decode the known
+	// fields with the default behavior.
+	type dispute Dispute
+
+	var v dispute
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+
+	*d = Dispute(v)
+	d.Raw = append(json.RawMessage(nil), data...)
+	d.Extra = extra
+
+	return nil
+}
+
+// Look up a value in the raw JSON of the dispute by a dot separated path of
+// object keys and array indexes, e.g. "fields.customer_name" or
+// "products.0.name". Useful for attributes the library doesn't know about yet.
+func (d *Dispute) Get(path string) (interface{}, bool) {
+	return getPath(d.Raw, path)
+}
+
+// Decodes the list in a single pass, keeping the raw JSON of each dispute but
+// not of the whole page.
+func (dl *DisputeList) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var list DisputeList
+	err := decodeDisputeListStream(json.NewDecoder(bytes.NewReader(data)), &list, func(d Dispute) error {
+		list.Data = append(list.Data, d)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*dl = list
+
+	return nil
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+
+	var v response
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	extra, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+
+	*r = Response(v)
+	r.Raw = append(json.RawMessage(nil), data...)
+	r.Extra = extra
+
+	return nil
+}
+
+// Look up a value in the raw JSON of the response by a dot separated path of
+// object keys and array indexes, e.g. "evidence.customer_name".
+func (r *Response) Get(path string) (interface{}, bool) {
+	return getPath(r.Raw, path)
+}
+
+// The lower case JSON names of the fields of struct types, by type.
+var knownFieldsCache sync.Map
+
+// Returns the JSON names of the struct's fields in lower case, since
+// encoding/json matches object keys to fields case insensitively.
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+
+		fields[strings.ToLower(name)] = true
+	}
+
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// Returns the attributes of the JSON object that don't match a field of the
+// struct type, or nil if there are none.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	known := knownFields(t)
+
+	var extra map[string]json.RawMessage
+	for key, value := range all {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = value
+	}
+
+	return extra, nil
+}
+
+// Look up a value in raw JSON by a dot separated path of object keys and
+// array indexes. Objects decode as map[string]interface{}, arrays as
+// []interface{} and numbers as float64.
+func getPath(raw json.RawMessage, path string) (interface{}, bool) {
+	if len(raw) == 0 {
+		return nil, false
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false
+	}
+
+	if path == "" {
+		return value, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,140 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func TestDisputeUnknownFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"id": "dp_xxx",
+			"state": "needs_response",
+			"fields": {"customer_name": "Susie"},
+			"products": [{"name": "prod1"}],
+			"network_reason_code": "10.4",
+			"evidence_deadline": {"days": 3}
+		}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	dispute, err := ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dispute.ID != "dp_xxx" || dispute.State != chargehound.DisputeStateNeedsResponse {
+		t.Error("Known fields not decoded.")
+	}
+
+	if len(dispute.Extra) != 2 || string(dispute.Extra["network_reason_code"]) != `"10.4"` {
+		t.Error("Incorrect unknown fields: ", dispute.Extra)
+	}
+
+	if v, ok := dispute.Get("network_reason_code"); !ok || v != "10.4" {
+		t.Error("Incorrect value: ", v)
+	}
+
+	if v, ok := dispute.Get("evidence_deadline.days"); !ok || v != 3.0 {
+		t.Error("Incorrect nested value: ", v)
+	}
+
+	if v, ok := dispute.Get("products.0.name"); !ok || v != "prod1" {
+		t.Error("Incorrect array value: ", v)
+	}
+
+	if v, ok := dispute.Get("fields.customer_name"); !ok || v != "Susie" {
+		t.Error("Incorrect field value: ", v)
+	}
+
+	for _, path := range []string{"missing", "products.1.name", "products.x", "id.x"} {
+		if _, ok := dispute.Get(path); ok {
+			t.Error("Unexpected value for: ", path)
+		}
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(dispute.Raw, &raw); err != nil || raw["id"] != "dp_xxx" {
+		t.Error("Incorrect raw json: ", string(dispute.Raw))
+	}
+}
+
+func TestDisputeListUnknownFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"object": "list",
+			"data": [{"id": "dp_1", "new_attribute": true}],
+			"has_more": false,
+			"total_count": 1
+		}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	list, err := ch.Disputes.List(&chargehound.ListDisputesParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := list.Extra["total_count"]; !ok || len(list.Extra) != 1 {
+		t.Error("Incorrect unknown list fields: ", list.Extra)
+	}
+
+	if v, ok := list.Data[0].Get("new_attribute"); !ok || v != true {
+		t.Error("Incorrect dispute value: ", v)
+	}
+
+	var streamed []chargehound.Dispute
+	list, err = ch.Disputes.ListStream(&chargehound.ListDisputesParams{}, func(d chargehound.Dispute) error {
+		streamed = append(streamed, d)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(list.Extra["total_count"]) != "1" {
+		t.Error("Incorrect streamed unknown list fields: ", list.Extra)
+	}
+
+	if string(streamed[0].Extra["new_attribute"]) != "true" {
+		t.Error("Incorrect streamed dispute fields: ", streamed[0].Extra)
+	}
+}
+
+func TestResponseUnknownFields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"dispute_id": "dp_xxx", "evidence": {"customer_name": "Susie"}, "page_count": 4}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	response, err := ch.Disputes.Response(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.DisputeID != "dp_xxx" {
+		t.Error("Known fields not decoded.")
+	}
+
+	if v, ok := response.Get("page_count"); !ok || v != 4.0 {
+		t.Error("Incorrect value: ", v)
+	}
+
+	if v, ok := response.Get("evidence.customer_name"); !ok || v != "Susie" {
+		t.Error("Incorrect evidence value: ", v)
+	}
+
+	if len(response.Extra) != 1 {
+		t.Error("Incorrect unknown fields: ", response.Extra)
+	}
+}
@@ -1,24 +1,37 @@
 package chargehound
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 )
 
 type apiRequestor struct {
-	APIKey      string
-	APIVersion  string
-	userAgent   string
-	bodyJSON    io.Reader
-	httpClient  *http.Client
-	method      string
-	queryParams *url.Values
-	url         string
+	APIKey              string
+	APIVersion          string
+	userAgent           string
+	autoIdempotencyKeys bool
+	body                io.Reader
+	bodyJSON            []byte
+	contentType         string
+	ctx                 context.Context
+	httpClient          *http.Client
+	idempotencyKey      string
+	maxResponseBytes    int64
+	method              string
+	queryParams         *url.Values
+	retryPolicy         *RetryPolicy
+	skipAuth            bool
+	url                 string
 }
 
-func newAPIRequestor(cc *Client, optHTTP *http.Client, method, path string, bodyJSON io.Reader, queryParams *url.Values) (*apiRequestor, error) {
+func newAPIRequestor(ctx context.Context, cc *Client, optHTTP *http.Client, method, path string, bodyJSON []byte, queryParams *url.Values) (*apiRequestor, error) {
 	var HTTPClient *http.Client
 
 	if optHTTP != nil {
@@ -34,44 +47,203 @@ func newAPIRequestor(cc *Client, optHTTP *http.Client, method, path string, body
 	}
 
 	requestor := apiRequestor{
-		APIKey:      cc.APIKey,
-		APIVersion:  cc.APIVersion,
-		bodyJSON:    bodyJSON,
-		httpClient:  HTTPClient,
-		method:      method,
-		queryParams: queryParams,
-		url:         url,
-		userAgent:   "Chargehound/v1 GoBindings/" + cc.Version,
+		APIKey:              cc.APIKey,
+		APIVersion:          cc.APIVersion,
+		autoIdempotencyKeys: cc.AutoIdempotencyKeys,
+		bodyJSON:            bodyJSON,
+		ctx:                 ctx,
+		httpClient:          HTTPClient,
+		maxResponseBytes:    cc.MaxResponseBytes,
+		method:              method,
+		queryParams:         queryParams,
+		retryPolicy:         cc.RetryPolicy,
+		url:                 url,
+		userAgent:           "Chargehound/v1 GoBindings/" + cc.Version,
 	}
 
 	return &requestor, nil
 }
 
-func (ar *apiRequestor) newRequest(v interface{}) (*http.Response, error) {
-	req, err := http.NewRequest(ar.method, ar.url, ar.bodyJSON)
+func (ar *apiRequestor) buildRequest() (*http.Request, error) {
+	body := ar.body
+	if ar.bodyJSON != nil {
+		body = bytes.NewReader(ar.bodyJSON)
+	}
+
+	req, err := http.NewRequestWithContext(ar.ctx, ar.method, ar.url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(ar.APIKey, "")
+	// Allow the body to be sent again on redirects and HTTP/2 retries.
+	if ar.bodyJSON != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(ar.bodyJSON)), nil
+		}
+	}
+
+	if !ar.skipAuth {
+		req.SetBasicAuth(ar.APIKey, "")
+	}
 
 	req.Header.Add("User-Agent", ar.userAgent)
-	req.Header.Add("Content-Type", "application/json")
+
+	if ar.contentType != "" {
+		req.Header.Add("Content-Type", ar.contentType)
+	} else {
+		req.Header.Add("Content-Type", "application/json")
+	}
 
 	if ar.APIVersion != "" {
 		req.Header.Add("Chargehound-Version", ar.APIVersion)
 	}
 
-	res, err := ar.httpClient.Do(req)
+	if ar.idempotencyKey != "" {
+		req.Header.Add("Idempotency-Key", ar.idempotencyKey)
+	}
+
+	return req, nil
+}
+
+// Generates a random (version 4) UUID to use as an idempotency key.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func (ar *apiRequestor) newRequest(v interface{}) (*http.Response, error) {
+	return ar.newStreamRequest(func(decoder *json.Decoder) error {
+		return decoder.Decode(&v)
+	})
+}
+
+// Send the request and decode the response body with the given function, which
+// may read the body incrementally.
+func (ar *apiRequestor) newStreamRequest(decode func(*json.Decoder) error) (*http.Response, error) {
+	res, err := ar.do()
 	if err != nil {
 		return nil, err
 	}
+	defer drainAndClose(res.Body)
+
+	if err := decode(json.NewDecoder(res.Body)); err != nil {
+		return res, newDecodeError(res, err)
+	}
+
+	return res, nil
+}
+
+// Send the request, retrying it according to the retry policy. Error
+// responses are returned as errors, otherwise the caller must close the
+// response body.
+func (ar *apiRequestor) do() (*http.Response, error) {
+	// The same key is sent on every attempt so the API can recognize retries
+	// of the same logical request.
+	if ar.idempotencyKey == "" && ar.autoIdempotencyKeys && ar.method == "POST" {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+		ar.idempotencyKey = key
+	}
+
+	idempotent := isIdempotentMethod(ar.method) || ar.idempotencyKey != ""
+
+	// A streamed body can only be read once, so it is never retried.
+	retryPolicy := ar.retryPolicy
+	if ar.body != nil {
+		retryPolicy = nil
+	}
+
+	var res *http.Response
+	for attempt := 1; ; attempt++ {
+		req, err := ar.buildRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		res, err = ar.httpClient.Do(req)
+		if err != nil {
+			// Surface cancellation and deadlines as the context's own error so
+			// callers can compare against context.Canceled or
+			// context.DeadlineExceeded.
+			if ctxErr := ar.ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			err = &TransportError{Method: req.Method, Path: req.URL.Path, Err: err}
+		}
+
+		if !retryPolicy.shouldRetry(attempt, idempotent, res, err) {
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		delay := retryPolicy.delay(attempt, res)
+		if res != nil {
+			drainAndClose(res.Body)
+		}
+
+		if err := sleepContext(ar.ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+
+	if ar.maxResponseBytes > 0 {
+		res.Body = &limitedBody{ReadCloser: res.Body, remaining: ar.maxResponseBytes}
+	}
 
 	if res.StatusCode >= 400 {
+		defer drainAndClose(res.Body)
 		return nil, responseToError(res)
 	}
 
-	decoder := json.NewDecoder(res.Body)
-	err = decoder.Decode(&v)
-	return res, err
+	return res, nil
+}
+
+// The maximum number of unread bytes discarded before closing a response
+// body. Larger bodies close the connection rather than reuse it.
+const maxDrainBytes = 64 << 10
+
+// Reads what remains of a response body and closes it so the underlying
+// connection can be reused.
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, maxDrainBytes))
+	body.Close()
+}
+
+// A response body that fails once more than the allowed number of bytes are
+// read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	if lb.remaining <= 0 {
+		// Check for a single extra byte to tell a body of exactly the
+		// allowed size from one that is too large.
+		var b [1]byte
+		n, err := lb.ReadCloser.Read(b[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > lb.remaining {
+		p = p[:lb.remaining]
+	}
+
+	n, err := lb.ReadCloser.Read(p)
+	lb.remaining -= int64(n)
+	return n, err
 }
@@ -0,0 +1,106 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func TestResponseBodiesReleaseConnections(t *testing.T) {
+	var mu sync.Mutex
+	opened := 0
+	requests := 0
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		mu.Unlock()
+
+		switch {
+		case n%3 == 0:
+			w.WriteHeader(404)
+			w.Write([]byte("{\"error\": { \"status\": 404, \"message\": \"Not found\"}}"))
+		case n%3 == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(503)
+			w.Write([]byte("{\"error\": { \"status\": 503, \"message\": \"Unavailable\"}}"))
+		default:
+			json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+		}
+
+		// Trailing whitespace the JSON decoder never reads.
+		w.Write([]byte(strings.Repeat(" ", 16<<10)))
+	}))
+	ts.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			opened++
+			mu.Unlock()
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	policy := chargehound.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+
+	ch := chargehound.New("api_key", &chargehound.ClientParams{RetryPolicy: policy})
+	transport := &http.Transport{MaxIdleConnsPerHost: 1}
+	defer transport.CloseIdleConnections()
+	ch.HTTPClient = &http.Client{Transport: transport}
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	for i := 0; i < 20; i++ {
+		ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if requests < 20 {
+		t.Error("Expected at least 20 requests, got: ", requests)
+	}
+
+	if opened != 1 {
+		t.Error("Expected a single reused connection, got: ", opened)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	ch := chargehound.New("api_key", &chargehound.ClientParams{MaxResponseBytes: 64})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_" + strings.Repeat("x", 128)})
+	}))
+	defer ts.Close()
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	_, err = ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if !errors.Is(err, chargehound.ErrResponseTooLarge) {
+		t.Error("Expected response too large, got: ", err)
+	}
+}
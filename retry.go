@@ -0,0 +1,160 @@
+package chargehound
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// A retry policy for failed API requests. Set on the client to retry
+// transient failures, a nil policy makes a single attempt per request.
+//
+// Requests that may not be safe to repeat, such as submitting or accepting a
+// dispute, are only retried when they carry an idempotency key or when the API
+// could not have processed them: the connection was never established or the
+// API responded with 429 Too Many Requests.
+type RetryPolicy struct {
+	// The maximum number of attempts, including the first one.
+	MaxAttempts int
+	// The delay before the first retry. The delay doubles on every retry.
+	BaseDelay time.Duration
+	// The maximum delay between attempts, including delays requested by a
+	// Retry-After header. Zero means no maximum.
+	MaxDelay time.Duration
+	// The fraction of each delay that is randomized, between 0 and 1.
+	Jitter float64
+	// The HTTP status codes that are retried.
+	RetryableStatusCodes []int
+	// Retry connection resets, timeouts and other network errors.
+	RetryNetworkErrors bool
+}
+
+// Returns a retry policy suitable for most clients: up to 3 attempts with
+// exponential backoff for rate limiting, server errors and network errors.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            500 * time.Millisecond,
+		MaxDelay:             10 * time.Second,
+		Jitter:               0.5,
+		RetryableStatusCodes: append([]int(nil), temporaryStatusCodes...),
+		RetryNetworkErrors:   true,
+	}
+}
+
+// Returns true if another attempt should be made after the given response
+// or error. Idempotent requests are safe to repeat even if the API may have
+// processed them.
+func (rp *RetryPolicy) shouldRetry(attempt int, idempotent bool, res *http.Response, err error) bool {
+	if rp == nil || attempt >= rp.MaxAttempts {
+		return false
+	}
+
+	if err != nil {
+		if !rp.RetryNetworkErrors {
+			return false
+		}
+		if idempotent {
+			return IsRetryable(err)
+		}
+		return isDialError(err)
+	}
+
+	if !rp.retryableStatus(res.StatusCode) {
+		return false
+	}
+
+	return idempotent || res.StatusCode == http.StatusTooManyRequests
+}
+
+func (rp *RetryPolicy) retryableStatus(code int) bool {
+	for _, c := range rp.RetryableStatusCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the delay before the next attempt. A Retry-After header on the
+// response takes precedence over the exponential backoff.
+func (rp *RetryPolicy) delay(attempt int, res *http.Response) time.Duration {
+	var d time.Duration
+
+	if retryAfter, ok := parseRetryAfter(res); ok {
+		d = retryAfter
+	} else {
+		// Stop doubling before the delay overflows when there is no maximum.
+		d = rp.BaseDelay
+		for i := 1; i < attempt && (rp.MaxDelay <= 0 || d < rp.MaxDelay) && d <= math.MaxInt64/2; i++ {
+			d *= 2
+		}
+
+		if rp.Jitter > 0 {
+			jitter := rp.Jitter
+			if jitter > 1 {
+				jitter = 1
+			}
+			d -= time.Duration(jitter * rand.Float64() * float64(d))
+		}
+	}
+
+	if rp.MaxDelay > 0 && d > rp.MaxDelay {
+		d = rp.MaxDelay
+	}
+
+	return d
+}
+
+// Parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// Waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		return true
+	}
+	return false
+}
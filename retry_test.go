@@ -0,0 +1,248 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func newRetryClient(t *testing.T, ts *httptest.Server) *chargehound.Client {
+	policy := chargehound.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+
+	ch := chargehound.New("api_key", &chargehound.ClientParams{RetryPolicy: policy})
+
+	url, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Error(err)
+	}
+
+	ch.Host = url.Host
+	ch.Protocol = url.Scheme + "://"
+
+	return ch
+}
+
+func TestRetryServerError(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			http.Error(w, "{\"error\": { \"status\": 503, \"message\": \"Unavailable\"}}", 503)
+			return
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	dispute, err := ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if attempts != 3 {
+		t.Error("Expected 3 attempts, got: ", attempts)
+	}
+
+	if dispute.ID != "dp_xxx" {
+		t.Error("Incorrect dispute id.")
+	}
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "{\"error\": { \"status\": 500, \"message\": \"Server error\"}}", 500)
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err == nil {
+		t.Error("Expected an error.")
+	}
+
+	if attempts != ch.RetryPolicy.MaxAttempts {
+		t.Error("Expected max attempts, got: ", attempts)
+	}
+}
+
+func TestRetryUpdateBody(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		b := make(map[string]interface{})
+		err := json.NewDecoder(r.Body).Decode(&b)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if b["template"] != "tmpl_1" {
+			t.Error("Incorrect template id.")
+		}
+
+		if attempts < 2 {
+			http.Error(w, "{\"error\": { \"status\": 502, \"message\": \"Bad gateway\"}}", 502)
+			return
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Disputes.Update(&chargehound.UpdateDisputeParams{ID: "dp_xxx", Template: "tmpl_1"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if attempts != 2 {
+		t.Error("Expected 2 attempts, got: ", attempts)
+	}
+}
+
+func TestNoRetryPostServerError(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "{\"error\": { \"status\": 500, \"message\": \"Server error\"}}", 500)
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Disputes.Accept(&chargehound.AcceptDisputeParams{ID: "dp_xxx"})
+	if err == nil {
+		t.Error("Expected an error.")
+	}
+
+	if attempts != 1 {
+		t.Error("Expected 1 attempt, got: ", attempts)
+	}
+}
+
+func TestRetryPostRateLimited(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "{\"error\": { \"status\": 429, \"message\": \"Slow down\"}}", 429)
+			return
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Disputes.Submit(&chargehound.UpdateDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if attempts != 2 {
+		t.Error("Expected 2 attempts, got: ", attempts)
+	}
+}
+
+func TestNoRetryPolicy(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "{\"error\": { \"status\": 503, \"message\": \"Unavailable\"}}", 503)
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+	ch.RetryPolicy = nil
+
+	_, err := ch.Disputes.Retrieve(&chargehound.RetrieveDisputeParams{ID: "dp_xxx"})
+	if err == nil {
+		t.Error("Expected an error.")
+	}
+
+	if attempts != 1 {
+		t.Error("Expected 1 attempt, got: ", attempts)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Idempotency-Key") != "key_1" {
+			t.Error("Incorrect idempotency key.")
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Disputes.Create(&chargehound.CreateDisputeParams{ID: "dp_xxx", IdempotencyKey: "key_1"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNoIdempotencyKeyByDefault(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Idempotency-Key") != "" {
+			t.Error("Unexpected idempotency key.")
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+
+	_, err := ch.Disputes.Accept(&chargehound.AcceptDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRetryPostWithIdempotencyKey(t *testing.T) {
+	var keys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) < 2 {
+			http.Error(w, "{\"error\": { \"status\": 500, \"message\": \"Server error\"}}", 500)
+			return
+		}
+
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_xxx"})
+	}))
+	defer ts.Close()
+
+	ch := newRetryClient(t, ts)
+	ch.AutoIdempotencyKeys = true
+
+	_, err := ch.Disputes.Submit(&chargehound.UpdateDisputeParams{ID: "dp_xxx"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(keys) != 2 {
+		t.Fatal("Expected 2 attempts, got: ", len(keys))
+	}
+
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Error("Idempotency key not reused across retries: ", keys)
+	}
+}
@@ -0,0 +1,100 @@
+package chargehound
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Decode a dispute list object token by token, calling fn with each dispute
+// in `data` as soon as it is decoded. The other list attributes are set on
+// list, and the raw JSON of the list is not kept.
+func decodeDisputeListStream(decoder *json.Decoder, list *DisputeList, fn func(Dispute) error) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		tok, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key, _ := tok.(string)
+
+		var value interface{}
+		switch key {
+		case "data":
+			if err := decodeDisputeArrayStream(decoder, fn); err != nil {
+				return err
+			}
+			continue
+		case "has_more":
+			value = &list.HasMore
+		case "livemode":
+			value = &list.Livemode
+		case "object":
+			value = &list.Object
+		case "url":
+			value = &list.URL
+		default:
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				return err
+			}
+
+			if list.Extra == nil {
+				list.Extra = make(map[string]json.RawMessage)
+			}
+			list.Extra[key] = raw
+			continue
+		}
+
+		if err := decoder.Decode(value); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+// Decode an array of disputes, or null, one element at a time.
+func decodeDisputeArrayStream(decoder *json.Decoder, fn func(Dispute) error) error {
+	tok, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if tok == nil {
+		return nil
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("chargehound: expected dispute array, got %v", tok)
+	}
+
+	for decoder.More() {
+		var d Dispute
+		if err := decoder.Decode(&d); err != nil {
+			return err
+		}
+
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+	tok, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("chargehound: expected %v, got %v", want, tok)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func TestListStream(t *testing.T) {
+	received := make(chan string, 3)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/disputes" {
+			t.Error("Incorrect path.")
+		}
+
+		if r.URL.RawQuery != "limit=3" {
+			t.Error("Incorrect query.")
+		}
+
+		w.Write([]byte(`{"object": "list", "url": "/v1/disputes", "unknown": {"a": [1, 2]}, "data": [`))
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_1"})
+		w.(http.Flusher).Flush()
+
+		// The rest of the page is only sent once the first dispute was
+		// handled, which requires the client to decode it incrementally.
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Error("First dispute not streamed.")
+		}
+
+		w.Write([]byte(","))
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_2"})
+		w.Write([]byte(","))
+		json.NewEncoder(w).Encode(chargehound.Dispute{ID: "dp_3"})
+		w.Write([]byte(`], "has_more": true, "livemode": true}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	var ids []string
+	list, err := ch.Disputes.ListStream(&chargehound.ListDisputesParams{Limit: 3}, func(d chargehound.Dispute) error {
+		ids = append(ids, d.ID)
+		received <- d.ID
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(ids) != "[dp_1 dp_2 dp_3]" {
+		t.Error("Incorrect disputes: ", ids)
+	}
+
+	if !list.HasMore || !list.Livemode || list.Object != "list" || list.URL != "/v1/disputes" {
+		t.Error("Incorrect list attributes: ", list)
+	}
+
+	if list.Data != nil {
+		t.Error("Disputes kept in memory.")
+	}
+
+	if list.Response.Status != 200 {
+		t.Error("Missing response status code.")
+	}
+}
+
+func TestListStreamCallbackError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(chargehound.DisputeList{
+			Data: []chargehound.Dispute{{ID: "dp_1"}, {ID: "dp_2"}},
+		})
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	stop := errors.New("stop")
+	calls := 0
+	_, err := ch.Disputes.ListStream(&chargehound.ListDisputesParams{}, func(d chargehound.Dispute) error {
+		calls++
+		return stop
+	})
+
+	if err != stop {
+		t.Error("Expected the callback error, got: ", err)
+	}
+
+	if calls != 1 {
+		t.Error("Expected 1 call, got: ", calls)
+	}
+}
+
+func TestListStreamMalformed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"id": "dp_1"}}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	_, err := ch.Disputes.ListStream(&chargehound.ListDisputesParams{}, func(d chargehound.Dispute) error {
+		return nil
+	})
+
+	var decodeErr *chargehound.DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Error("Expected a decode error, got: ", err)
+	}
+}
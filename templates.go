@@ -0,0 +1,248 @@
+package chargehound
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Wrapper for the Chargehound API templates resource.
+type Templates struct {
+	client *Client
+}
+
+// A template for dispute responses, set on a dispute by its id.
+type Template struct {
+	// A unique identifier for the template.
+	ID string `json:"id"`
+	// The template name.
+	Name string `json:"name"`
+	// A description of the template.
+	Description string `json:"description"`
+	// The fields the template uses.
+	Fields []TemplateField `json:"fields"`
+	// ISO 8601 timestamp - when the template was created.
+	Created Timestamp `json:"created"`
+	// ISO 8601 timestamp - when the template was last updated.
+	Updated Timestamp `json:"updated"`
+	// Set to `template`.
+	Object string `json:"object"`
+	// Is this a test or live mode template.
+	Livemode bool `json:"livemode"`
+	// The HTTP response.
+	Response HTTPResponse `json:"-"`
+}
+
+// A field used by a template.
+type TemplateField struct {
+	// The field name, the key in a dispute's Fields.
+	Name string `json:"name"`
+	// The type of the field's value.
+	Type FieldType `json:"type"`
+	// Whether a dispute needs the field to be submitted with the template.
+	Required bool `json:"required"`
+	// A description of the field.
+	Description string `json:"description"`
+}
+
+// The type of a template field's value. Values the library doesn't know about
+// yet are decoded as is and are not valid according to IsValid.
+type FieldType string
+
+const (
+	FieldTypeText    = FieldType("text")
+	FieldTypeNumber  = FieldType("number")
+	FieldTypeDate    = FieldType("date")
+	FieldTypeURL     = FieldType("url")
+	FieldTypeEmail   = FieldType("email")
+	FieldTypeBoolean = FieldType("boolean")
+)
+
+// Reports whether the type is one of the known field types.
+func (t FieldType) IsValid() bool {
+	switch t {
+	case FieldTypeText, FieldTypeNumber, FieldTypeDate, FieldTypeURL,
+		FieldTypeEmail, FieldTypeBoolean:
+		return true
+	}
+	return false
+}
+
+func (t FieldType) String() string {
+	return string(t)
+}
+
+// The type returned by a list templates request.
+type TemplateList struct {
+	Data     []Template   `json:"data"`
+	HasMore  bool         `json:"has_more"`
+	Livemode bool         `json:"livemode"`
+	Object   string       `json:"object"`
+	URL      string       `json:"url"`
+	Response HTTPResponse `json:"-"`
+}
+
+// Params for a retrieve template request.
+type RetrieveTemplateParams struct {
+	// The template id.
+	ID string
+	// Optional http client for the request. Typically needed when using App Engine.
+	OptHTTPClient *http.Client
+}
+
+// Params for a list templates request.
+type ListTemplatesParams struct {
+	Limit         int
+	StartingAfter string
+	EndingBefore  string
+	// Optional http client for the request. Typically needed when using App Engine.
+	OptHTTPClient *http.Client
+}
+
+// Retrieve a single template.
+func (tp *Templates) Retrieve(params *RetrieveTemplateParams) (*Template, error) {
+	return tp.RetrieveWithContext(context.Background(), params)
+}
+
+// Retrieve a single template using the provided context for cancellation and deadlines.
+func (tp *Templates) RetrieveWithContext(ctx context.Context, params *RetrieveTemplateParams) (*Template, error) {
+	req, err := newAPIRequestor(
+		ctx,
+		tp.client,
+		params.OptHTTPClient,
+		"GET",
+		fmt.Sprintf("templates/%s", params.ID),
+		nil, // no body json
+		nil, // no query params
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var v Template
+	res, err := req.newRequest(&v)
+	if err == nil {
+		v.Response = HTTPResponse{Status: res.StatusCode}
+	}
+
+	return &v, err
+}
+
+// Retrieve a list of templates.
+func (tp *Templates) List(params *ListTemplatesParams) (*TemplateList, error) {
+	return tp.ListWithContext(context.Background(), params)
+}
+
+// Retrieve a list of templates using the provided context for cancellation and deadlines.
+func (tp *Templates) ListWithContext(ctx context.Context, params *ListTemplatesParams) (*TemplateList, error) {
+	q := url.Values{}
+
+	if params.Limit > 0 {
+		q.Set("limit", strconv.Itoa(params.Limit))
+	}
+
+	if params.StartingAfter != "" {
+		q.Set("starting_after", params.StartingAfter)
+	} else if params.EndingBefore != "" {
+		q.Set("ending_before", params.EndingBefore)
+	}
+
+	req, err := newAPIRequestor(
+		ctx,
+		tp.client,
+		params.OptHTTPClient,
+		"GET",
+		"templates",
+		nil, // no body json
+		&q,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var v TemplateList
+	res, err := req.newRequest(&v)
+	if err == nil {
+		v.Response = HTTPResponse{Status: res.StatusCode}
+	}
+
+	return &v, err
+}
+
+// Check a dispute's Fields against the template, before submitting a dispute
+// with it. Returns ValidationErrors for required fields that are missing or
+// empty, and for values that don't match their field's type. Fields the
+// template doesn't use, and fields of unknown types, are not checked.
+func (t *Template) ValidateFields(fields map[string]interface{}) error {
+	var v validator
+
+	for _, f := range t.Fields {
+		field := "fields." + f.Name
+		value, ok := fields[f.Name]
+
+		if !ok || value == nil || value == "" {
+			v.required(field, f.Required)
+			continue
+		}
+
+		switch f.Type {
+		case FieldTypeText:
+			if _, ok := value.(string); !ok {
+				v.add(field, "must be text")
+			}
+		case FieldTypeNumber:
+			if !isNumber(value) {
+				v.add(field, "must be a number")
+			}
+		case FieldTypeDate:
+			if !isDate(value) {
+				v.add(field, "must be an ISO 8601 date")
+			}
+		case FieldTypeURL:
+			if s, ok := value.(string); ok {
+				v.url(field, s)
+			} else {
+				v.add(field, "must be an absolute http or https URL")
+			}
+		case FieldTypeEmail:
+			if s, ok := value.(string); !ok || !strings.Contains(s, "@") {
+				v.add(field, "must be an email address")
+			}
+		case FieldTypeBoolean:
+			if _, ok := value.(bool); !ok {
+				v.add(field, "must be true or false")
+			}
+		}
+	}
+
+	return v.err()
+}
+
+func isNumber(value interface{}) bool {
+	switch n := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	case json.Number:
+		_, err := n.Float64()
+		return err == nil
+	}
+	return false
+}
+
+func isDate(value interface{}) bool {
+	switch d := value.(type) {
+	case time.Time, Timestamp, *Timestamp:
+		return true
+	case string:
+		_, err := ParseTimestamp(d)
+		return err == nil
+	}
+	return false
+}
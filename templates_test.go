@@ -0,0 +1,138 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+var templateJSON = `{
+	"id": "crossed_wires",
+	"name": "Crossed wires",
+	"object": "template",
+	"fields": [
+		{"name": "customer_name", "type": "text", "required": true},
+		{"name": "customer_email", "type": "email", "required": true},
+		{"name": "order_total", "type": "number", "required": false},
+		{"name": "shipped_at", "type": "date", "required": false},
+		{"name": "tracking_url", "type": "url", "required": false},
+		{"name": "signed_for", "type": "boolean", "required": false},
+		{"name": "signature", "type": "drawing", "required": false}
+	]
+}`
+
+func TestRetrieveTemplate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/v1/templates/crossed_wires" {
+			t.Error("Incorrect request: ", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(templateJSON))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	template, err := ch.Templates.Retrieve(&chargehound.RetrieveTemplateParams{ID: "crossed_wires"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if template.ID != "crossed_wires" || template.Response.Status != 200 || len(template.Fields) != 7 {
+		t.Fatal("Incorrect template: ", template)
+	}
+
+	field := template.Fields[0]
+	if field.Name != "customer_name" || field.Type != chargehound.FieldTypeText || !field.Required {
+		t.Error("Incorrect field: ", field)
+	}
+
+	if template.Fields[6].Type.IsValid() {
+		t.Error("Expected an unknown field type to be invalid.")
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/templates" {
+			t.Error("Incorrect path: ", r.URL.Path)
+		}
+
+		if r.URL.Query().Get("limit") != "2" || r.URL.Query().Get("starting_after") != "first" {
+			t.Error("Incorrect query: ", r.URL.RawQuery)
+		}
+
+		w.Write([]byte(`{"object": "list", "has_more": true, "data": [` + templateJSON + `, {"id": "other"}]}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+
+	list, err := ch.Templates.List(&chargehound.ListTemplatesParams{Limit: 2, StartingAfter: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Data) != 2 || !list.HasMore || list.Data[1].ID != "other" {
+		t.Error("Incorrect list: ", list)
+	}
+}
+
+func TestTemplateValidateFields(t *testing.T) {
+	var template chargehound.Template
+	if err := json.Unmarshal([]byte(templateJSON), &template); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]interface{}{
+		"customer_name":  "Susie",
+		"customer_email": "susie@example.com",
+		"order_total":    12.5,
+		"shipped_at":     time.Now(),
+		"tracking_url":   "https://example.com/track/1",
+		"signed_for":     true,
+		"signature":      []int{1, 2, 3},
+		"unused":         "ignored",
+	}
+	if err := template.ValidateFields(valid); err != nil {
+		t.Error("Expected valid fields, got: ", err)
+	}
+
+	if err := template.ValidateFields(map[string]interface{}{
+		"customer_name":  "Susie",
+		"customer_email": "susie@example.com",
+		"shipped_at":     "2024-05-01",
+		"order_total":    json.Number("12"),
+	}); err != nil {
+		t.Error("Expected valid fields, got: ", err)
+	}
+
+	invalid := map[string]interface{}{
+		"customer_name": "",
+		"order_total":   "12.50",
+		"shipped_at":    "yesterday",
+		"tracking_url":  "/track/1",
+		"signed_for":    "yes",
+	}
+
+	fields := validationFields(t, template.ValidateFields(invalid))
+	expected := []string{
+		"fields.customer_name",
+		"fields.customer_email",
+		"fields.order_total",
+		"fields.shipped_at",
+		"fields.tracking_url",
+		"fields.signed_for",
+	}
+	if len(fields) != len(expected) {
+		t.Fatal("Expected fields ", expected, ", got: ", fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Error("Expected field ", expected[i], ", got: ", fields[i])
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package chargehound
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// A timestamp from the API. Decodes ISO 8601 strings, Unix timestamps in
+// seconds and null, and encodes as an ISO 8601 string, or null if zero. The
+// embedded time.Time is the zero time for a null or empty timestamp.
+type Timestamp struct {
+	time.Time
+}
+
+// Layouts accepted for ISO 8601 strings, in order. Timestamps without a time
+// zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// Parse an ISO 8601 timestamp.
+func ParseTimestamp(value string) (Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{t}, nil
+		}
+	}
+
+	return Timestamp{}, fmt.Errorf("chargehound: invalid ISO 8601 timestamp %q", value)
+}
+
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	if ts.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(ts.UTC().Format(time.RFC3339Nano))
+}
+
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+		*ts = Timestamp{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+
+		if value == "" {
+			*ts = Timestamp{}
+			return nil
+		}
+
+		parsed, err := ParseTimestamp(value)
+		if err != nil {
+			return err
+		}
+
+		*ts = parsed
+		return nil
+	}
+
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("chargehound: invalid timestamp %s", data)
+	}
+
+	whole, frac := math.Modf(seconds)
+	*ts = Timestamp{time.Unix(int64(whole), int64(frac*1e9)).UTC()}
+	return nil
+}
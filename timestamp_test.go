@@ -0,0 +1,103 @@
+package chargehound_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+var timestampTests = []struct {
+	json     string
+	expected time.Time
+}{
+	{`"2016-10-18T20:38:51Z"`, time.Date(2016, 10, 18, 20, 38, 51, 0, time.UTC)},
+	{`"2016-10-18T22:38:51+02:00"`, time.Date(2016, 10, 18, 20, 38, 51, 0, time.UTC)},
+	{`"2016-10-18T20:38:51.250Z"`, time.Date(2016, 10, 18, 20, 38, 51, 250e6, time.UTC)},
+	{`"2016-10-18T20:38:51"`, time.Date(2016, 10, 18, 20, 38, 51, 0, time.UTC)},
+	{`"2016-10-18"`, time.Date(2016, 10, 18, 0, 0, 0, 0, time.UTC)},
+	{`1476823131`, time.Date(2016, 10, 18, 20, 38, 51, 0, time.UTC)},
+	{`1476823131.5`, time.Date(2016, 10, 18, 20, 38, 51, 5e8, time.UTC)},
+	{`null`, time.Time{}},
+	{`""`, time.Time{}},
+}
+
+func TestTimestampUnmarshal(t *testing.T) {
+	for _, test := range timestampTests {
+		var ts chargehound.Timestamp
+		if err := json.Unmarshal([]byte(test.json), &ts); err != nil {
+			t.Error(test.json, err)
+			continue
+		}
+
+		if !ts.Equal(test.expected) {
+			t.Error("Incorrect time for ", test.json, ": ", ts.Time)
+		}
+	}
+}
+
+func TestTimestampUnmarshalInvalid(t *testing.T) {
+	for _, value := range []string{`"yesterday"`, `true`, `{}`} {
+		var ts chargehound.Timestamp
+		if err := json.Unmarshal([]byte(value), &ts); err == nil {
+			t.Error("Expected an error for: ", value)
+		}
+	}
+}
+
+func TestTimestampMarshal(t *testing.T) {
+	b, err := json.Marshal(chargehound.Timestamp{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != "null" {
+		t.Error("Incorrect zero timestamp: ", string(b))
+	}
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	b, err = json.Marshal(chargehound.Timestamp{Time: time.Date(2016, 10, 18, 22, 38, 51, 0, loc)})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if string(b) != `"2016-10-18T20:38:51Z"` {
+		t.Error("Incorrect timestamp: ", string(b))
+	}
+}
+
+func TestDisputeTimestamps(t *testing.T) {
+	var dispute chargehound.Dispute
+	err := json.Unmarshal([]byte(`{
+		"charged_at": "2016-10-01T22:20:53",
+		"disputed_at": 1475360453,
+		"due_by": "2016-10-18T20:38:51Z",
+		"submitted_at": null,
+		"correspondence": [{"sent": "2016-09-30T10:00:00Z"}],
+		"past_payments": [{"charged_at": 1475000000}]
+	}`), &dispute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dispute.ChargedAt.Format(time.RFC3339) != "2016-10-01T22:20:53Z" {
+		t.Error("Incorrect charged at: ", dispute.ChargedAt)
+	}
+
+	if dispute.DisputedAt.Unix() != 1475360453 {
+		t.Error("Incorrect disputed at: ", dispute.DisputedAt)
+	}
+
+	if !dispute.SubmittedAt.IsZero() {
+		t.Error("Expected no submitted at.")
+	}
+
+	if dispute.Correspondence[0].Sent.Format(time.RFC3339) != "2016-09-30T10:00:00Z" {
+		t.Error("Incorrect correspondence sent: ", dispute.Correspondence[0].Sent)
+	}
+
+	if dispute.PastPayments[0].ChargedAt.Unix() != 1475000000 {
+		t.Error("Incorrect past payment charged at: ", dispute.PastPayments[0].ChargedAt)
+	}
+}
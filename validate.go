@@ -0,0 +1,193 @@
+package chargehound
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Matched by ValidationErrors with errors.Is.
+var ErrInvalidParams = errors.New("chargehound: invalid params")
+
+// A problem with a single request param.
+type ValidationError struct {
+	// The JSON name of the param, e.g. "charge" or "products.0.url".
+	Field string
+	// What is wrong with the param.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Every problem found validating request params, returned by Validate.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "chargehound: invalid params: " + strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// Collects validation errors.
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, message string) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Message: message})
+}
+
+// The collected errors, or nil.
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func (v *validator) required(field string, missing bool) {
+	if missing {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) nonNegative(field string, amount int) {
+	if amount < 0 {
+		v.add(field, "must not be negative")
+	}
+}
+
+func (v *validator) enum(field, value string, valid bool) {
+	if value != "" && !valid {
+		v.add(field, "unknown value "+strconv.Quote(value))
+	}
+}
+
+// Currencies are three letter ISO 4217 codes, in any case.
+func (v *validator) currency(field, currency string) {
+	if currency == "" {
+		return
+	}
+
+	if len(currency) != 3 {
+		v.add(field, "must be a three letter ISO 4217 currency code")
+		return
+	}
+
+	for _, c := range currency {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			v.add(field, "must be a three letter ISO 4217 currency code")
+			return
+		}
+	}
+}
+
+// URLs must be absolute http or https URLs.
+func (v *validator) url(field, value string) {
+	if value == "" {
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.add(field, "must be an absolute http or https URL")
+	}
+}
+
+func (v *validator) products(products []Product) {
+	for i, p := range products {
+		field := "products." + strconv.Itoa(i)
+		v.nonNegative(field+".amount", p.Amount)
+		v.nonNegative(field+".quantity", p.Quantity)
+		v.url(field+".url", p.URL)
+	}
+}
+
+func (v *validator) pastPayments(payments []PastPayment) {
+	for i, p := range payments {
+		field := "past_payments." + strconv.Itoa(i)
+		v.nonNegative(field+".amount", p.Amount)
+		v.currency(field+".currency", p.Currency)
+	}
+}
+
+// Fields can't be both forced and null, and must be sent in update requests.
+func (v *validator) updateFields(forceSend, null []UpdateField) {
+	nulled := make(map[UpdateField]bool, len(null))
+	for _, name := range null {
+		if !name.IsValid() {
+			v.add("null_fields", "unknown field "+strconv.Quote(string(name)))
+		}
+		nulled[name] = true
+	}
+
+	for _, name := range forceSend {
+		if !name.IsValid() {
+			v.add("force_send_fields", "unknown field "+strconv.Quote(string(name)))
+		} else if nulled[name] {
+			v.add("force_send_fields", "field "+strconv.Quote(string(name))+" is also null")
+		}
+	}
+}
+
+// Check the params for missing required fields and invalid values before
+// sending them, returning ValidationErrors with every problem found.
+func (params *CreateDisputeParams) Validate() error {
+	var v validator
+
+	v.required("id", params.ID == "")
+	v.required("charge", params.Charge == "")
+	v.required("reason", params.Reason == "")
+	v.required("charged_at", params.ChargedAt.IsZero())
+	v.required("disputed_at", params.DisputedAt.IsZero())
+	v.required("due_by", params.DueBy.IsZero())
+	v.required("currency", params.Currency == "")
+
+	v.enum("reason", string(params.Reason), params.Reason.IsValid())
+	v.enum("processor", string(params.Processor), params.Processor.IsValid())
+	v.enum("kind", string(params.Kind), params.Kind.IsValid())
+	v.enum("state", string(params.State),
+		params.State == DisputeStateNeedsResponse || params.State == DisputeStateWarningNeedsResponse)
+	v.enum("address_line1_check", string(params.AddressLine1Check), params.AddressLine1Check.IsValid())
+	v.enum("address_zip_check", string(params.AddressZipCheck), params.AddressZipCheck.IsValid())
+	v.enum("cvc_check", string(params.CVCCheck), params.CVCCheck.IsValid())
+
+	v.currency("currency", params.Currency)
+	v.currency("reversal_currency", params.ReversalCurrency)
+
+	v.nonNegative("amount", params.Amount)
+	v.nonNegative("fee", params.Fee)
+	v.nonNegative("reversal_amount", params.ReversalAmount)
+	v.nonNegative("reversal_total", params.ReversalTotal)
+	v.nonNegative("submitted_count", params.SubmittedCount)
+
+	v.url("reference_url", params.ReferenceURL)
+	v.products(params.Products)
+	v.pastPayments(params.PastPayments)
+
+	return v.err()
+}
+
+// Check the params for missing required fields and invalid values before
+// sending them, returning ValidationErrors with every problem found.
+func (params *UpdateDisputeParams) Validate() error {
+	var v validator
+
+	v.required("id", params.ID == "")
+	v.url("reference_url", params.ReferenceURL)
+	v.products(params.Products)
+	v.pastPayments(params.PastPayments)
+
+	v.updateFields(params.ForceSendFields, params.NullFields)
+
+	return v.err()
+}
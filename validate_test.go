@@ -0,0 +1,185 @@
+package chargehound_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+func validCreateParams() *chargehound.CreateDisputeParams {
+	now := chargehound.Timestamp{Time: time.Now()}
+
+	return &chargehound.CreateDisputeParams{
+		ID:         "dp_123",
+		Charge:     "ch_123",
+		Reason:     chargehound.DisputeReasonFraudulent,
+		ChargedAt:  now,
+		DisputedAt: now,
+		DueBy:      now,
+		Currency:   "usd",
+		Amount:     500,
+	}
+}
+
+func validationFields(t *testing.T, err error) []string {
+	var invalid chargehound.ValidationErrors
+	if !errors.As(err, &invalid) {
+		t.Fatal("Expected validation errors, got: ", err)
+	}
+
+	if !errors.Is(err, chargehound.ErrInvalidParams) {
+		t.Error("Expected error to match ErrInvalidParams")
+	}
+
+	fields := make([]string, len(invalid))
+	for i, e := range invalid {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+func TestValidateCreateDispute(t *testing.T) {
+	if err := validCreateParams().Validate(); err != nil {
+		t.Error("Expected valid params, got: ", err)
+	}
+
+	err := (&chargehound.CreateDisputeParams{}).Validate()
+	expected := []string{"id", "charge", "reason", "charged_at", "disputed_at", "due_by", "currency"}
+	fields := validationFields(t, err)
+	if len(fields) != len(expected) {
+		t.Fatal("Expected fields ", expected, ", got: ", fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Error("Expected field ", expected[i], ", got: ", fields[i])
+		}
+	}
+}
+
+var invalidCreateTests = []struct {
+	field  string
+	modify func(*chargehound.CreateDisputeParams)
+}{
+	{"reason", func(p *chargehound.CreateDisputeParams) { p.Reason = "bogus" }},
+	{"processor", func(p *chargehound.CreateDisputeParams) { p.Processor = "bogus" }},
+	{"state", func(p *chargehound.CreateDisputeParams) { p.State = chargehound.DisputeStateWon }},
+	{"cvc_check", func(p *chargehound.CreateDisputeParams) { p.CVCCheck = "bogus" }},
+	{"currency", func(p *chargehound.CreateDisputeParams) { p.Currency = "dollars" }},
+	{"reversal_currency", func(p *chargehound.CreateDisputeParams) { p.ReversalCurrency = "u$d" }},
+	{"amount", func(p *chargehound.CreateDisputeParams) { p.Amount = -1 }},
+	{"reference_url", func(p *chargehound.CreateDisputeParams) { p.ReferenceURL = "example.com/order" }},
+	{"products.1.url", func(p *chargehound.CreateDisputeParams) {
+		p.Products = []chargehound.Product{{URL: "https://example.com"}, {URL: "ftp://example.com"}}
+	}},
+	{"past_payments.0.amount", func(p *chargehound.CreateDisputeParams) {
+		p.PastPayments = []chargehound.PastPayment{{Amount: -100, Currency: "usd"}}
+	}},
+}
+
+func TestValidateCreateDisputeInvalid(t *testing.T) {
+	for _, test := range invalidCreateTests {
+		params := validCreateParams()
+		test.modify(params)
+
+		fields := validationFields(t, params.Validate())
+		if len(fields) != 1 || fields[0] != test.field {
+			t.Error("Expected field ", test.field, ", got: ", fields)
+		}
+	}
+}
+
+func TestValidateUpdateDispute(t *testing.T) {
+	params := &chargehound.UpdateDisputeParams{ID: "dp_123", ReferenceURL: "https://example.com/order"}
+	if err := params.Validate(); err != nil {
+		t.Error("Expected valid params, got: ", err)
+	}
+
+	params = &chargehound.UpdateDisputeParams{ReferenceURL: "not a url"}
+	fields := validationFields(t, params.Validate())
+	if len(fields) != 2 || fields[0] != "id" || fields[1] != "reference_url" {
+		t.Error("Incorrect fields: ", fields)
+	}
+}
+
+func TestValidateParamsBeforeRequest(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"id": "dp_123"}`))
+	}))
+	defer ts.Close()
+
+	ch := newTestClient(t, ts)
+	ch.ValidateParams = true
+
+	_, err := ch.Disputes.Submit(&chargehound.UpdateDisputeParams{})
+	if !errors.Is(err, chargehound.ErrInvalidParams) {
+		t.Error("Expected ErrInvalidParams, got: ", err)
+	}
+
+	_, err = ch.Disputes.Create(&chargehound.CreateDisputeParams{ID: "dp_123"})
+	if !errors.Is(err, chargehound.ErrInvalidParams) {
+		t.Error("Expected ErrInvalidParams, got: ", err)
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Error("Expected no requests, got: ", n)
+	}
+
+	if _, err := ch.Disputes.Update(&chargehound.UpdateDisputeParams{ID: "dp_123"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateUpdateDisputeClearFields(t *testing.T) {
+	params := &chargehound.UpdateDisputeParams{
+		ID:              "dp_123",
+		ForceSendFields: []chargehound.UpdateField{chargehound.UpdateFieldForce, "Bogus", chargehound.UpdateFieldTemplate},
+		NullFields:      []chargehound.UpdateField{chargehound.UpdateFieldTemplate, "ID"},
+	}
+
+	fields := validationFields(t, params.Validate())
+	expected := []string{"null_fields", "force_send_fields", "force_send_fields"}
+	if len(fields) != len(expected) {
+		t.Fatal("Expected fields ", expected, ", got: ", fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Error("Expected field ", expected[i], ", got: ", fields[i])
+		}
+	}
+}
+
+func TestUpdateFieldsValid(t *testing.T) {
+	fields := []chargehound.UpdateField{
+		chargehound.UpdateFieldTemplate,
+		chargehound.UpdateFieldCharge,
+		chargehound.UpdateFieldAccount,
+		chargehound.UpdateFieldAccountID,
+		chargehound.UpdateFieldReferenceURL,
+		chargehound.UpdateFieldForce,
+		chargehound.UpdateFieldQueue,
+		chargehound.UpdateFieldSubmit,
+		chargehound.UpdateFieldFields,
+		chargehound.UpdateFieldProducts,
+		chargehound.UpdateFieldCorrespondence,
+		chargehound.UpdateFieldPastPayments,
+	}
+
+	for _, f := range fields {
+		if !f.IsValid() {
+			t.Error("Expected a valid update field: ", f)
+		}
+	}
+
+	for _, f := range []chargehound.UpdateField{"ID", "IdempotencyKey", "template"} {
+		if f.IsValid() {
+			t.Error("Expected an invalid update field: ", f)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package webhook
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Returned by EventStore.Begin when another delivery of the event is being
+// handled.
+var ErrEventInProgress = errors.New("webhook: event in progress")
+
+// Records which events were processed, so redelivered events are skipped,
+// and which failed, so they can be replayed with Handler.Replay.
+// Implementations must be safe for concurrent use.
+type EventStore interface {
+	// Reports whether the event was already processed.
+	Processed(ctx context.Context, eventID string) (bool, error)
+	// Claim the event before handling it. Returns false if it was already
+	// processed, and ErrEventInProgress if another delivery has claimed it.
+	// Checking and claiming must be atomic, so that concurrent deliveries of
+	// an event are handled once.
+	Begin(ctx context.Context, eventID string) (bool, error)
+	// Record that the event was processed, releasing its claim and clearing
+	// any recorded failure.
+	MarkProcessed(ctx context.Context, eventID string) error
+	// Record that handling the event failed, releasing its claim.
+	RecordFailure(ctx context.Context, failure Failure) error
+	// The recorded failures, oldest first.
+	Failures(ctx context.Context) ([]Failure, error)
+}
+
+// An event whose callback returned an error.
+type Failure struct {
+	// The id of the event.
+	EventID string `json:"event_id"`
+	// The verified request body, replayed as is.
+	Payload json.RawMessage `json:"payload"`
+	// The callback's error message.
+	Error string `json:"error"`
+	// How many times handling the event has failed.
+	Attempts int `json:"attempts"`
+	// When handling the event first failed.
+	FirstFailedAt time.Time `json:"first_failed_at"`
+	// When handling the event last failed.
+	LastFailedAt time.Time `json:"last_failed_at"`
+}
+
+// An EventStore held in memory. It remembers the most recently processed
+// event ids up to its capacity, forgetting the least recently seen first,
+// and as many failures, forgetting the oldest first.
+type MemoryStore struct {
+	mu           sync.Mutex
+	capacity     int
+	order        *list.List
+	processed    map[string]*list.Element
+	failureOrder *list.List
+	failures     map[string]*list.Element
+	inProgress   map[string]bool
+}
+
+// Create a store remembering up to capacity processed event ids and up to
+// capacity failures. A capacity of zero or less means no limit.
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity:     capacity,
+		order:        list.New(),
+		processed:    make(map[string]*list.Element),
+		failureOrder: list.New(),
+		failures:     make(map[string]*list.Element),
+		inProgress:   make(map[string]bool),
+	}
+}
+
+func (s *MemoryStore) Processed(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.processed[eventID]
+	if ok {
+		s.order.MoveToFront(e)
+	}
+	return ok, nil
+}
+
+func (s *MemoryStore) Begin(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.processed[eventID]; ok {
+		s.order.MoveToFront(e)
+		return false, nil
+	}
+
+	if s.inProgress[eventID] {
+		return false, ErrEventInProgress
+	}
+
+	s.inProgress[eventID] = true
+	return true, nil
+}
+
+func (s *MemoryStore) MarkProcessed(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.markProcessed(eventID)
+	return nil
+}
+
+func (s *MemoryStore) markProcessed(eventID string) {
+	delete(s.inProgress, eventID)
+
+	if e, ok := s.failures[eventID]; ok {
+		s.failureOrder.Remove(e)
+		delete(s.failures, eventID)
+	}
+
+	if e, ok := s.processed[eventID]; ok {
+		s.order.MoveToFront(e)
+		return
+	}
+
+	s.processed[eventID] = s.order.PushFront(eventID)
+	if s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.processed, oldest.Value.(string))
+	}
+}
+
+func (s *MemoryStore) RecordFailure(ctx context.Context, failure Failure) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recordFailure(failure)
+	return nil
+}
+
+// Merge the failure into any earlier one for the same event.
+func (s *MemoryStore) recordFailure(failure Failure) {
+	if failure.Attempts == 0 {
+		failure.Attempts = 1
+	}
+	if failure.LastFailedAt.IsZero() {
+		failure.LastFailedAt = time.Now()
+	}
+	if failure.FirstFailedAt.IsZero() {
+		failure.FirstFailedAt = failure.LastFailedAt
+	}
+
+	delete(s.inProgress, failure.EventID)
+
+	if e, ok := s.failures[failure.EventID]; ok {
+		prev := e.Value.(Failure)
+		failure.Attempts += prev.Attempts
+		failure.FirstFailedAt = prev.FirstFailedAt
+		e.Value = failure
+		return
+	}
+
+	s.failures[failure.EventID] = s.failureOrder.PushFront(failure)
+	if s.capacity > 0 && s.failureOrder.Len() > s.capacity {
+		oldest := s.failureOrder.Back()
+		s.failureOrder.Remove(oldest)
+		delete(s.failures, oldest.Value.(Failure).EventID)
+	}
+}
+
+func (s *MemoryStore) Failures(ctx context.Context) ([]Failure, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.failureList(), nil
+}
+
+func (s *MemoryStore) failureList() []Failure {
+	failures := make([]Failure, 0, s.failureOrder.Len())
+	for e := s.failureOrder.Back(); e != nil; e = e.Prev() {
+		failures = append(failures, e.Value.(Failure))
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].FirstFailedAt.Before(failures[j].FirstFailedAt)
+	})
+
+	return failures
+}
+
+// A copy of the store's state, keeping the order of processed ids and
+// failures. The copy has its own lock.
+func (s *MemoryStore) clone() *MemoryStore {
+	c := NewMemoryStore(s.capacity)
+	for e := s.order.Back(); e != nil; e = e.Prev() {
+		id := e.Value.(string)
+		c.processed[id] = c.order.PushFront(id)
+	}
+	for e := s.failureOrder.Back(); e != nil; e = e.Prev() {
+		f := e.Value.(Failure)
+		c.failures[f.EventID] = c.failureOrder.PushFront(f)
+	}
+	for id := range s.inProgress {
+		c.inProgress[id] = true
+	}
+	return c
+}
+
+// Processed event ids, least recently seen first.
+func (s *MemoryStore) processedList() []string {
+	ids := make([]string, 0, s.order.Len())
+	for e := s.order.Back(); e != nil; e = e.Prev() {
+		ids = append(ids, e.Value.(string))
+	}
+	return ids
+}
+
+// An EventStore saved to a JSON file, so processed events and failures are
+// remembered across restarts. The file is rewritten atomically on every
+// change, which suits the modest volume of webhook notifications; use a
+// database backed EventStore for more. Claims made by Begin are held in
+// memory only, so events being handled when the process stops are handled
+// again when they are redelivered.
+type FileStore struct {
+	mem  *MemoryStore
+	path string
+}
+
+type fileStoreJSON struct {
+	Processed []string  `json:"processed"`
+	Failures  []Failure `json:"failures"`
+}
+
+// Open the store saved at path, creating it on the first change if it does
+// not exist. Remembers up to capacity processed event ids and failures, see
+// NewMemoryStore.
+func NewFileStore(path string, capacity int) (*FileStore, error) {
+	s := &FileStore{mem: NewMemoryStore(capacity), path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved fileStoreJSON
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+
+	for _, id := range saved.Processed {
+		s.mem.markProcessed(id)
+	}
+	for _, f := range saved.Failures {
+		s.mem.recordFailure(f)
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) Processed(ctx context.Context, eventID string) (bool, error) {
+	return s.mem.Processed(ctx, eventID)
+}
+
+func (s *FileStore) Begin(ctx context.Context, eventID string) (bool, error) {
+	return s.mem.Begin(ctx, eventID)
+}
+
+// Save the change before applying it in memory, so an event isn't skipped as
+// processed unless that was saved.
+func (s *FileStore) MarkProcessed(ctx context.Context, eventID string) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	next := s.mem.clone()
+	next.markProcessed(eventID)
+	return s.apply(next, eventID)
+}
+
+func (s *FileStore) RecordFailure(ctx context.Context, failure Failure) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	next := s.mem.clone()
+	next.recordFailure(failure)
+	return s.apply(next, failure.EventID)
+}
+
+// Save the changed state and, if that succeeds, make it the current one.
+// Either way the event's claim is released, so a redelivery is handled again.
+// Called with the lock held.
+func (s *FileStore) apply(next *MemoryStore, eventID string) error {
+	if err := s.save(next); err != nil {
+		delete(s.mem.inProgress, eventID)
+		return err
+	}
+
+	s.mem.order = next.order
+	s.mem.processed = next.processed
+	s.mem.failureOrder = next.failureOrder
+	s.mem.failures = next.failures
+	s.mem.inProgress = next.inProgress
+	return nil
+}
+
+func (s *FileStore) Failures(ctx context.Context) ([]Failure, error) {
+	return s.mem.Failures(ctx)
+}
+
+// Write the store to a temporary file and rename it over the old one, so a
+// crash never leaves a partial file. Called with the lock held.
+func (s *FileStore) save(state *MemoryStore) error {
+	data, err := json.Marshal(fileStoreJSON{
+		Processed: state.processedList(),
+		Failures:  state.failureList(),
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
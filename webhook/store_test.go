@@ -0,0 +1,326 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9/webhook"
+)
+
+func TestMemoryStoreEvictsLeastRecent(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore(2)
+
+	store.MarkProcessed(ctx, "wh_1")
+	store.MarkProcessed(ctx, "wh_2")
+	// Seeing wh_1 again makes wh_2 the least recent.
+	store.Processed(ctx, "wh_1")
+	store.MarkProcessed(ctx, "wh_3")
+
+	for id, expected := range map[string]bool{"wh_1": true, "wh_2": false, "wh_3": true} {
+		if processed, _ := store.Processed(ctx, id); processed != expected {
+			t.Error("Incorrect processed for ", id, ": ", processed)
+		}
+	}
+}
+
+func TestMemoryStoreFailures(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore(0)
+
+	store.RecordFailure(ctx, webhook.Failure{EventID: "wh_1", Error: "first"})
+	store.RecordFailure(ctx, webhook.Failure{EventID: "wh_1", Error: "second"})
+	store.RecordFailure(ctx, webhook.Failure{EventID: "wh_2", Error: "other"})
+
+	failures, _ := store.Failures(ctx)
+	if len(failures) != 2 || failures[0].EventID != "wh_1" {
+		t.Fatal("Incorrect failures: ", failures)
+	}
+
+	if failures[0].Attempts != 2 || failures[0].Error != "second" {
+		t.Error("Incorrect merged failure: ", failures[0])
+	}
+
+	store.MarkProcessed(ctx, "wh_1")
+	if failures, _ := store.Failures(ctx); len(failures) != 1 || failures[0].EventID != "wh_2" {
+		t.Error("Expected processing to clear the failure, got: ", failures)
+	}
+}
+
+func TestFileStorePersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.json")
+
+	store, err := webhook.NewFileStore(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.MarkProcessed(ctx, "wh_1"); err != nil {
+		t.Error(err)
+	}
+	if err := store.RecordFailure(ctx, webhook.Failure{EventID: "wh_2", Payload: closedPayload, Error: "failed"}); err != nil {
+		t.Error(err)
+	}
+
+	reopened, err := webhook.NewFileStore(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if processed, _ := reopened.Processed(ctx, "wh_1"); !processed {
+		t.Error("Expected wh_1 to be processed after reopening.")
+	}
+
+	failures, _ := reopened.Failures(ctx)
+	if len(failures) != 1 || failures[0].EventID != "wh_2" || failures[0].Attempts != 1 {
+		t.Fatal("Incorrect failures after reopening: ", failures)
+	}
+
+	if failures[0].FirstFailedAt.IsZero() || failures[0].Error != "failed" {
+		t.Error("Incorrect failure details: ", failures[0])
+	}
+}
+
+func TestFileStoreSaveFailure(t *testing.T) {
+	ctx := context.Background()
+	// The directory doesn't exist, so the store can't be saved.
+	path := filepath.Join(t.TempDir(), "missing", "events.json")
+
+	store, err := webhook.NewFileStore(path, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &webhook.Handler{
+		Secret: secret,
+		Store:  store,
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(closedPayload, time.Now()))
+	if w.Code != http.StatusInternalServerError {
+		t.Error("Incorrect status: ", w.Code)
+	}
+
+	if processed, _ := store.Processed(ctx, "wh_123"); processed {
+		t.Error("Expected wh_123 not to be processed when saving failed.")
+	}
+
+	claimed, err := store.Begin(ctx, "wh_123")
+	if err != nil || !claimed {
+		t.Error("Expected the redelivery to be handled again: ", claimed, err)
+	}
+
+	if err := store.RecordFailure(ctx, webhook.Failure{EventID: "wh_123", Error: "failed"}); err == nil {
+		t.Error("Expected an error saving the failure.")
+	}
+
+	if failures, _ := store.Failures(ctx); len(failures) != 0 {
+		t.Error("Expected no failures when saving failed: ", failures)
+	}
+}
+
+func TestHandlerSkipsDuplicates(t *testing.T) {
+	calls := 0
+	handler := &webhook.Handler{
+		Secret: secret,
+		Store:  webhook.NewMemoryStore(100),
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			calls++
+			return nil
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, signedRequest(closedPayload, time.Now()))
+		if w.Code != http.StatusOK {
+			t.Error("Incorrect status: ", w.Code)
+		}
+	}
+
+	if calls != 1 {
+		t.Error("Expected one call, got: ", calls)
+	}
+}
+
+func TestHandlerReplaysFailures(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore(100)
+
+	fail := true
+	calls := 0
+	handler := &webhook.Handler{
+		Secret: secret,
+		Store:  store,
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			calls++
+			if fail {
+				return errors.New("database unavailable")
+			}
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(closedPayload, time.Now()))
+	if w.Code != http.StatusInternalServerError {
+		t.Error("Expected 500, got: ", w.Code)
+	}
+
+	if n, err := handler.Replay(ctx); n != 0 || err == nil {
+		t.Error("Expected the replay to fail, got: ", n, err)
+	}
+
+	failures, _ := store.Failures(ctx)
+	if len(failures) != 1 || failures[0].Attempts != 2 {
+		t.Fatal("Incorrect failures: ", failures)
+	}
+
+	fail = false
+	if n, err := handler.Replay(ctx); n != 1 || err != nil {
+		t.Error("Expected one event replayed, got: ", n, err)
+	}
+
+	if failures, _ := store.Failures(ctx); len(failures) != 0 {
+		t.Error("Expected no failures, got: ", failures)
+	}
+
+	if processed, _ := store.Processed(ctx, "wh_123"); !processed {
+		t.Error("Expected the replayed event to be processed.")
+	}
+
+	if calls != 3 {
+		t.Error("Expected three calls, got: ", calls)
+	}
+}
+
+func TestMemoryStoreBegin(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore(10)
+
+	if claimed, err := store.Begin(ctx, "wh_1"); !claimed || err != nil {
+		t.Fatal("Expected to claim the event, got: ", claimed, err)
+	}
+
+	if claimed, err := store.Begin(ctx, "wh_1"); claimed || !errors.Is(err, webhook.ErrEventInProgress) {
+		t.Error("Expected the event to be in progress, got: ", claimed, err)
+	}
+
+	// A failure releases the claim so the event can be handled again.
+	store.RecordFailure(ctx, webhook.Failure{EventID: "wh_1", Error: "failed"})
+	if claimed, err := store.Begin(ctx, "wh_1"); !claimed || err != nil {
+		t.Error("Expected to claim the failed event, got: ", claimed, err)
+	}
+
+	store.MarkProcessed(ctx, "wh_1")
+	if claimed, err := store.Begin(ctx, "wh_1"); claimed || err != nil {
+		t.Error("Expected the event to be processed, got: ", claimed, err)
+	}
+}
+
+func TestMemoryStoreFailureCapacity(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore(2)
+
+	for _, id := range []string{"wh_1", "wh_2", "wh_3"} {
+		store.RecordFailure(ctx, webhook.Failure{EventID: id, Payload: closedPayload})
+	}
+
+	failures, _ := store.Failures(ctx)
+	if len(failures) != 2 || failures[0].EventID != "wh_2" || failures[1].EventID != "wh_3" {
+		t.Error("Expected the oldest failure to be dropped, got: ", failures)
+	}
+}
+
+func TestHandlerConcurrentDeliveries(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	handler := &webhook.Handler{
+		Secret: secret,
+		Store:  webhook.NewMemoryStore(100),
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			atomic.AddInt32(&calls, 1)
+			close(started)
+			<-release
+			return nil
+		},
+	}
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, signedRequest(closedPayload, time.Now()))
+		close(done)
+	}()
+
+	<-started
+
+	// A redelivery while the first is being handled is turned away.
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, signedRequest(closedPayload, time.Now()))
+	if second.Code != http.StatusConflict {
+		t.Error("Expected 409, got: ", second.Code)
+	}
+
+	close(release)
+	<-done
+
+	if first.Code != http.StatusOK {
+		t.Error("Expected 200, got: ", first.Code)
+	}
+
+	// Once processed, redeliveries are acknowledged.
+	third := httptest.NewRecorder()
+	handler.ServeHTTP(third, signedRequest(closedPayload, time.Now()))
+	if third.Code != http.StatusOK {
+		t.Error("Expected 200, got: ", third.Code)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Error("Expected one call, got: ", n)
+	}
+}
+
+func TestHandlerPanicReleasesClaim(t *testing.T) {
+	ctx := context.Background()
+	store := webhook.NewMemoryStore(100)
+
+	handler := &webhook.Handler{
+		Secret: secret,
+		Store:  store,
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			panic("boom")
+		},
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to propagate.")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), signedRequest(closedPayload, time.Now()))
+	}()
+
+	failures, _ := store.Failures(ctx)
+	if len(failures) != 1 || failures[0].Error != "panic: boom" {
+		t.Error("Expected the panic to be recorded, got: ", failures)
+	}
+
+	if claimed, err := store.Begin(ctx, "wh_123"); !claimed || err != nil {
+		t.Error("Expected the claim to be released, got: ", claimed, err)
+	}
+}
@@ -0,0 +1,374 @@
+// Package webhook verifies and routes Chargehound webhook notifications.
+//
+//	handler := &webhook.Handler{
+//		Secret: "whsec_xxx",
+//		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+//			log.Println(event.ID, event.State)
+//			return nil
+//		},
+//	}
+//	http.Handle("/webhooks/chargehound", handler)
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+)
+
+// The request header carrying the webhook signature.
+const SignatureHeader = "Chargehound-Signature"
+
+// How far a signature's timestamp may be from the current time when a
+// Handler does not set its own tolerance.
+const DefaultTolerance = 5 * time.Minute
+
+// The largest request body a Handler reads when it does not set its own limit.
+const DefaultMaxBodyBytes = 1 << 20
+
+var (
+	// Returned when a request has no signature header or it can't be parsed.
+	ErrMissingSignature = errors.New("webhook: missing or malformed signature")
+	// Returned when no signature in the header matches the payload.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	// Returned when the signature's timestamp is outside the tolerance,
+	// e.g. a replayed request.
+	ErrTimestampOutsideTolerance = errors.New("webhook: timestamp outside tolerance")
+)
+
+type EventType string
+
+const (
+	DisputeCreated   = EventType("dispute.created")
+	DisputeUpdated   = EventType("dispute.updated")
+	DisputeSubmitted = EventType("dispute.submitted")
+	DisputeClosed    = EventType("dispute.closed")
+	DisputeExpired   = EventType("dispute.expired")
+)
+
+// A dispute notification. The dispute's fields are embedded, so event.ID and
+// event.Created are the dispute's, and event.EventID and event.EventCreated
+// are the event's own. When the notification carries only the dispute id,
+// just ID is set; retrieve the dispute for the rest.
+type DisputeEvent struct {
+	// A unique identifier for the event.
+	EventID string
+	// The type of the event, e.g. `dispute.closed`.
+	Type EventType
+	// Is this a test or live mode event.
+	Livemode bool
+	// ISO 8601 timestamp - when the event was created.
+	EventCreated chargehound.Timestamp
+	chargehound.Dispute
+}
+
+type eventJSON struct {
+	ID       string                `json:"id"`
+	Type     EventType             `json:"type"`
+	Livemode bool                  `json:"livemode"`
+	Created  chargehound.Timestamp `json:"created"`
+	Dispute  json.RawMessage       `json:"dispute"`
+}
+
+// Decodes the event envelope and the dispute it carries. Defined here so the
+// embedded dispute's decoding doesn't take over the whole event.
+func (e *DisputeEvent) UnmarshalJSON(data []byte) error {
+	var raw eventJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.EventID = raw.ID
+	e.Type = raw.Type
+	e.Livemode = raw.Livemode
+	e.EventCreated = raw.Created
+	e.Dispute = chargehound.Dispute{}
+
+	if len(raw.Dispute) == 0 {
+		return nil
+	}
+
+	// Notifications may carry the dispute id instead of the dispute object.
+	if raw.Dispute[0] == '"' {
+		return json.Unmarshal(raw.Dispute, &e.Dispute.ID)
+	}
+	return json.Unmarshal(raw.Dispute, &e.Dispute)
+}
+
+// A dispute was created.
+type DisputeCreatedEvent struct{ DisputeEvent }
+
+// A dispute was updated.
+type DisputeUpdatedEvent struct{ DisputeEvent }
+
+// A dispute response was submitted.
+type DisputeSubmittedEvent struct{ DisputeEvent }
+
+// A dispute was closed, won or lost.
+type DisputeClosedEvent struct{ DisputeEvent }
+
+// A dispute passed its due date without a response.
+type DisputeExpiredEvent struct{ DisputeEvent }
+
+// Compute the signature header for a payload sent at the given time. Useful
+// for testing handlers.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(computeSignature(secret, t, payload))
+}
+
+func computeSignature(secret, timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// Check the signature header against the payload, comparing in constant
+// time, and that its timestamp is within the tolerance of the current time.
+// A zero tolerance uses DefaultTolerance.
+func VerifySignature(payload []byte, header, secret string, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			if sig, err := hex.DecodeString(kv[1]); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+
+	expected := computeSignature(secret, timestamp, payload)
+	valid := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	age := time.Since(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrTimestampOutsideTolerance
+	}
+
+	return nil
+}
+
+// Verify the signature header and decode the event.
+func ParseEvent(payload []byte, header, secret string, tolerance time.Duration) (*DisputeEvent, error) {
+	if err := VerifySignature(payload, header, secret, tolerance); err != nil {
+		return nil, err
+	}
+
+	var event DisputeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("webhook: decoding event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// An http.Handler for webhook notifications. It verifies each request's
+// signature and routes the event to the callback for its type. Requests that
+// fail verification get a 400 response, and events whose callback returns an
+// error get a 500 response so that Chargehound delivers them again. Events
+// without a callback are acknowledged and ignored.
+//
+// With a Store, an event already processed is acknowledged without calling
+// its callback again, and a delivery that arrives while another delivery of
+// the same event is being handled gets a 409 response, to be delivered again
+// later. An event is handled again if its callback succeeds but recording
+// that in the store fails, so callbacks with side effects should still
+// tolerate running twice.
+type Handler struct {
+	// The webhook signing secret.
+	Secret string
+	// How far a signature's timestamp may be from the current time. Zero uses
+	// DefaultTolerance.
+	Tolerance time.Duration
+	// The largest request body read. Zero uses DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+	// Records processed events to skip redeliveries, and failed events to
+	// replay with Replay. (optional)
+	Store EventStore
+
+	OnDisputeCreated   func(context.Context, *DisputeCreatedEvent) error
+	OnDisputeUpdated   func(context.Context, *DisputeUpdatedEvent) error
+	OnDisputeSubmitted func(context.Context, *DisputeSubmittedEvent) error
+	OnDisputeClosed    func(context.Context, *DisputeClosedEvent) error
+	OnDisputeExpired   func(context.Context, *DisputeExpiredEvent) error
+	// Called for events without a callback for their type. (optional)
+	OnEvent func(context.Context, *DisputeEvent) error
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	maxBodyBytes := h.MaxBodyBytes
+	if maxBodyBytes == 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		http.Error(w, "reading body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(payload)) > maxBodyBytes {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	event, err := ParseEvent(payload, r.Header.Get(SignatureHeader), h.Secret, h.Tolerance)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.handle(r.Context(), event, payload); err != nil {
+		if errors.Is(err, ErrEventInProgress) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, "handling event", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Dispatch the event unless the store has already seen it, recording the
+// outcome in the store.
+func (h *Handler) handle(ctx context.Context, event *DisputeEvent, payload []byte) error {
+	if h.Store == nil {
+		return h.Dispatch(ctx, event)
+	}
+
+	claimed, err := h.Store.Begin(ctx, event.EventID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	// Release the claim if the callback panics, so the event isn't stuck in
+	// progress.
+	defer func() {
+		if r := recover(); r != nil {
+			h.Store.RecordFailure(ctx, Failure{
+				EventID: event.EventID,
+				Payload: payload,
+				Error:   fmt.Sprint("panic: ", r),
+			})
+			panic(r)
+		}
+	}()
+
+	if err := h.Dispatch(ctx, event); err != nil {
+		// The callback's error is what matters to the caller, even if the
+		// failure can't be recorded.
+		h.Store.RecordFailure(ctx, Failure{
+			EventID: event.EventID,
+			Payload: payload,
+			Error:   err.Error(),
+		})
+		return err
+	}
+
+	return h.Store.MarkProcessed(ctx, event.EventID)
+}
+
+// Handle every failure recorded in the store again, oldest first. Events
+// handled successfully are marked processed, and those that fail again have
+// their failure updated. Payloads were verified when first received, so their
+// signatures are not checked again. Returns the number of events replayed
+// successfully and the first error.
+func (h *Handler) Replay(ctx context.Context) (int, error) {
+	if h.Store == nil {
+		return 0, errors.New("webhook: replay needs a Store")
+	}
+
+	failures, err := h.Store.Failures(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	var firstErr error
+	for _, f := range failures {
+		if err := ctx.Err(); err != nil {
+			return replayed, err
+		}
+
+		var event DisputeEvent
+		err := json.Unmarshal(f.Payload, &event)
+		if err == nil {
+			err = h.handle(ctx, &event, f.Payload)
+		}
+
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("webhook: replaying %s: %w", f.EventID, err)
+			}
+			continue
+		}
+		replayed++
+	}
+
+	return replayed, firstErr
+}
+
+// Route a verified event to the callback for its type.
+func (h *Handler) Dispatch(ctx context.Context, event *DisputeEvent) error {
+	switch {
+	case event.Type == DisputeCreated && h.OnDisputeCreated != nil:
+		return h.OnDisputeCreated(ctx, &DisputeCreatedEvent{*event})
+	case event.Type == DisputeUpdated && h.OnDisputeUpdated != nil:
+		return h.OnDisputeUpdated(ctx, &DisputeUpdatedEvent{*event})
+	case event.Type == DisputeSubmitted && h.OnDisputeSubmitted != nil:
+		return h.OnDisputeSubmitted(ctx, &DisputeSubmittedEvent{*event})
+	case event.Type == DisputeClosed && h.OnDisputeClosed != nil:
+		return h.OnDisputeClosed(ctx, &DisputeClosedEvent{*event})
+	case event.Type == DisputeExpired && h.OnDisputeExpired != nil:
+		return h.OnDisputeExpired(ctx, &DisputeExpiredEvent{*event})
+	case h.OnEvent != nil:
+		return h.OnEvent(ctx, event)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chargehound/chargehound-go/v9"
+	"github.com/chargehound/chargehound-go/v9/webhook"
+)
+
+const secret = "whsec_test"
+
+var closedPayload = []byte(`{
+	"id": "wh_123",
+	"type": "dispute.closed",
+	"livemode": true,
+	"created": "2024-05-01T12:00:00Z",
+	"dispute": {"id": "dp_123", "state": "won", "amount": 500, "currency": "usd", "created": "2024-04-01T09:30:00Z"}
+}`)
+
+func signedRequest(payload []byte, timestamp time.Time) *http.Request {
+	r := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(payload))
+	r.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, timestamp, payload))
+	return r
+}
+
+func TestHandlerRoutesEvent(t *testing.T) {
+	var closed *webhook.DisputeClosedEvent
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			closed = event
+			return nil
+		},
+		OnEvent: func(ctx context.Context, event *webhook.DisputeEvent) error {
+			t.Error("Unexpected fallback for ", event.Type)
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(closedPayload, time.Now()))
+
+	if w.Code != http.StatusOK {
+		t.Fatal("Incorrect status: ", w.Code, w.Body.String())
+	}
+
+	if closed == nil {
+		t.Fatal("Expected the closed callback to be called.")
+	}
+
+	if closed.EventID != "wh_123" || closed.Type != webhook.DisputeClosed || !closed.Livemode {
+		t.Error("Incorrect event: ", closed.EventID, closed.Type, closed.Livemode)
+	}
+
+	if closed.ID != "dp_123" || closed.State != chargehound.DisputeStateWon || closed.Amount != 500 {
+		t.Error("Incorrect dispute: ", closed.ID, closed.State, closed.Amount)
+	}
+
+	if !closed.EventCreated.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Incorrect event created: ", closed.EventCreated)
+	}
+
+	if !closed.Created.Equal(time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Error("Incorrect dispute created: ", closed.Created)
+	}
+}
+
+func TestHandlerDisputeID(t *testing.T) {
+	payload := []byte(`{"id": "wh_456", "type": "dispute.updated", "object": "webhook", "livemode": false, "dispute": "dp_456"}`)
+
+	var updated *webhook.DisputeUpdatedEvent
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnDisputeUpdated: func(ctx context.Context, event *webhook.DisputeUpdatedEvent) error {
+			updated = event
+			return nil
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(payload, time.Now()))
+
+	if w.Code != http.StatusOK {
+		t.Fatal("Incorrect status: ", w.Code, w.Body.String())
+	}
+
+	if updated == nil {
+		t.Fatal("Expected the updated callback to be called.")
+	}
+
+	if updated.EventID != "wh_456" || updated.ID != "dp_456" {
+		t.Error("Incorrect event: ", updated.EventID, updated.ID)
+	}
+}
+
+func TestHandlerFallback(t *testing.T) {
+	var types []webhook.EventType
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnEvent: func(ctx context.Context, event *webhook.DisputeEvent) error {
+			types = append(types, event.Type)
+			return nil
+		},
+	}
+
+	payload := []byte(`{"id": "wh_456", "type": "dispute.won", "dispute": {"id": "dp_123"}}`)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(payload, time.Now()))
+
+	if w.Code != http.StatusOK || len(types) != 1 || types[0] != "dispute.won" {
+		t.Error("Incorrect fallback: ", w.Code, types)
+	}
+}
+
+func TestHandlerRejectsBadSignatures(t *testing.T) {
+	called := false
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnEvent: func(ctx context.Context, event *webhook.DisputeEvent) error {
+			called = true
+			return nil
+		},
+	}
+
+	unsigned := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(closedPayload))
+
+	wrongSecret := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(closedPayload))
+	wrongSecret.Header.Set(webhook.SignatureHeader, webhook.Sign("whsec_other", time.Now(), closedPayload))
+
+	tampered := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(append(closedPayload, ' ')))
+	tampered.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, time.Now(), closedPayload))
+
+	replayed := signedRequest(closedPayload, time.Now().Add(-time.Hour))
+
+	for _, r := range []*http.Request{unsigned, wrongSecret, tampered, replayed} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Error("Expected 400, got: ", w.Code)
+		}
+	}
+
+	if called {
+		t.Error("Expected no callbacks for rejected requests.")
+	}
+}
+
+func TestHandlerCallbackError(t *testing.T) {
+	handler := &webhook.Handler{
+		Secret: secret,
+		OnDisputeClosed: func(ctx context.Context, event *webhook.DisputeClosedEvent) error {
+			return errors.New("database unavailable")
+		},
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(closedPayload, time.Now()))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Error("Expected 500, got: ", w.Code)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Now()
+
+	header := webhook.Sign(secret, now, closedPayload)
+	if err := webhook.VerifySignature(closedPayload, header, secret, 0); err != nil {
+		t.Error(err)
+	}
+
+	// A rotated secret may send more than one signature.
+	old := webhook.Sign("whsec_old", now, closedPayload)
+	header += old[strings.Index(old, ","):]
+	if err := webhook.VerifySignature(closedPayload, header, secret, 0); err != nil {
+		t.Error(err)
+	}
+
+	err := webhook.VerifySignature(closedPayload, "garbage", secret, 0)
+	if !errors.Is(err, webhook.ErrMissingSignature) {
+		t.Error("Expected ErrMissingSignature, got: ", err)
+	}
+
+	err = webhook.VerifySignature(closedPayload, webhook.Sign(secret, now.Add(2*time.Minute), closedPayload), secret, time.Minute)
+	if !errors.Is(err, webhook.ErrTimestampOutsideTolerance) {
+		t.Error("Expected ErrTimestampOutsideTolerance, got: ", err)
+	}
+}